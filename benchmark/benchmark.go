@@ -0,0 +1,293 @@
+// Package benchmark runs a prompt set through a model and summarizes the
+// generation metrics reported by the Ollama server.
+package benchmark
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// DefaultPrompts is the prompt set used when none is configured.
+var DefaultPrompts = []string{
+	"Explain the difference between a process and a thread in two sentences.",
+	"Write a Go function that reverses a slice of strings in place.",
+	"Summarize the plot of Romeo and Juliet in one paragraph.",
+	"List five uses of a hash map, one per line.",
+}
+
+// Options configures a benchmark.
+type Options struct {
+	Prompts []string
+	// Runs is how many times the prompt set is run.
+	Runs int
+	// Cold unloads the model before every prompt so the load time is measured
+	// each time, instead of only on the first one.
+	Cold bool
+	// ModelOptions are the generation options sent with every prompt, e.g.
+	// "num_ctx". The model defaults are used when empty.
+	ModelOptions map[string]any
+}
+
+// Sample is the outcome of a single prompt.
+type Sample struct {
+	Prompt             string        `json:"prompt"`
+	LoadDuration       time.Duration `json:"load_duration"`
+	PromptEvalCount    int           `json:"prompt_eval_count"`
+	PromptEvalDuration time.Duration `json:"prompt_eval_duration"`
+	EvalCount          int           `json:"eval_count"`
+	EvalDuration       time.Duration `json:"eval_duration"`
+	TotalDuration      time.Duration `json:"total_duration"`
+	// TimeToFirstToken is measured by the client, from sending the request to
+	// receiving the first token.
+	TimeToFirstToken time.Duration `json:"time_to_first_token"`
+}
+
+// TokensPerSecond returns the generation speed.
+func (s Sample) TokensPerSecond() float64 {
+	return rate(s.EvalCount, s.EvalDuration)
+}
+
+// PromptTokensPerSecond returns the prompt evaluation speed.
+func (s Sample) PromptTokensPerSecond() float64 {
+	return rate(s.PromptEvalCount, s.PromptEvalDuration)
+}
+
+func rate(count int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(count) / d.Seconds()
+}
+
+// Stats summarizes a metric over every sample.
+type Stats struct {
+	Mean float64 `json:"mean"`
+	// StdDev is the sample standard deviation, 0 for a single sample.
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	P50    float64 `json:"p50"`
+	P90    float64 `json:"p90"`
+	P99    float64 `json:"p99"`
+	Max    float64 `json:"max"`
+}
+
+// Summary holds the stats of the metrics reported for a benchmark. Times are
+// in milliseconds.
+type Summary struct {
+	TokensPerSecond       Stats `json:"tokens_per_second"`
+	PromptTokensPerSecond Stats `json:"prompt_tokens_per_second"`
+	TimeToFirstToken      Stats `json:"time_to_first_token_ms"`
+	LoadTime              Stats `json:"load_time_ms"`
+}
+
+// Result is a finished benchmark of a model.
+type Result struct {
+	Time    time.Time      `json:"time"`
+	Host    string         `json:"host"`
+	Model   string         `json:"model"`
+	Digest  string         `json:"digest,omitempty"`
+	Runs    int            `json:"runs"`
+	Cold    bool           `json:"cold,omitempty"`
+	Options map[string]any `json:"options,omitempty"`
+	Samples []Sample       `json:"samples"`
+	Summary Summary        `json:"summary"`
+}
+
+// Run benchmarks the model, calling fn after every prompt with the number of
+// prompts done and the total.
+func Run(ctx context.Context, client *api.Client, model string, opts Options, fn func(done, total int, s Sample)) (Result, error) {
+	result := Result{
+		Time:    time.Now(),
+		Model:   model,
+		Runs:    opts.Runs,
+		Cold:    opts.Cold,
+		Options: opts.ModelOptions,
+	}
+
+	if len(opts.Prompts) == 0 {
+		return result, errors.New("the prompt set is empty")
+	}
+	if opts.Runs <= 0 {
+		return result, errors.New("the number of runs must be positive")
+	}
+
+	total := opts.Runs * len(opts.Prompts)
+	for run := range opts.Runs {
+		for i, prompt := range opts.Prompts {
+			if opts.Cold {
+				if err := unload(ctx, client, model); err != nil {
+					return result, err
+				}
+			}
+
+			s, err := Generate(ctx, client, model, prompt, opts.ModelOptions, nil)
+			if err != nil {
+				return result, err
+			}
+
+			result.Samples = append(result.Samples, s)
+			if fn != nil {
+				fn(run*len(opts.Prompts)+i+1, total, s)
+			}
+		}
+	}
+
+	result.Summary = Summarize(result.Samples)
+
+	return result, nil
+}
+
+// Generate sends the prompt to the model and returns its metrics, calling fn
+// (when not nil) with every streamed chunk of the response.
+func Generate(ctx context.Context, client *api.Client, model, prompt string, options map[string]any, fn func(chunk string)) (Sample, error) {
+	s := Sample{Prompt: prompt}
+
+	start := time.Now()
+	req := &api.GenerateRequest{
+		Model:   model,
+		Prompt:  prompt,
+		Options: options,
+	}
+
+	err := client.Generate(ctx, req, func(resp api.GenerateResponse) error {
+		if s.TimeToFirstToken == 0 && resp.Response != "" {
+			s.TimeToFirstToken = time.Since(start)
+		}
+		if fn != nil && resp.Response != "" {
+			fn(resp.Response)
+		}
+		if resp.Done {
+			s.LoadDuration = resp.LoadDuration
+			s.PromptEvalCount = resp.PromptEvalCount
+			s.PromptEvalDuration = resp.PromptEvalDuration
+			s.EvalCount = resp.EvalCount
+			s.EvalDuration = resp.EvalDuration
+			s.TotalDuration = resp.TotalDuration
+		}
+		return nil
+	})
+	if err != nil {
+		return s, fmt.Errorf("failed to generate: %s", err.Error())
+	}
+
+	return s, nil
+}
+
+func unload(ctx context.Context, client *api.Client, model string) error {
+	req := &api.GenerateRequest{
+		Model:     model,
+		KeepAlive: &api.Duration{Duration: 0},
+	}
+
+	err := client.Generate(ctx, req, func(api.GenerateResponse) error { return nil })
+	if err != nil {
+		return fmt.Errorf("failed to unload model: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Summarize computes the stats of every metric over the samples.
+func Summarize(samples []Sample) Summary {
+	var tps, ptps, ttft, load []float64
+	for _, s := range samples {
+		tps = append(tps, s.TokensPerSecond())
+		ptps = append(ptps, s.PromptTokensPerSecond())
+		ttft = append(ttft, milliseconds(s.TimeToFirstToken))
+		load = append(load, milliseconds(s.LoadDuration))
+	}
+
+	return Summary{
+		TokensPerSecond:       stats(tps),
+		PromptTokensPerSecond: stats(ptps),
+		TimeToFirstToken:      stats(ttft),
+		LoadTime:              stats(load),
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func stats(values []float64) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+
+	sorted := slices.Sorted(slices.Values(values))
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(len(sorted))
+
+	return Stats{
+		Mean:   mean,
+		StdDev: stddev(sorted, mean),
+		Min:    sorted[0],
+		P50:    percentile(sorted, 50),
+		P90:    percentile(sorted, 90),
+		P99:    percentile(sorted, 99),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// stddev returns the sample standard deviation of the values around their
+// mean.
+func stddev(values []float64, mean float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+
+	return math.Sqrt(squares / float64(len(values)-1))
+}
+
+// percentile interpolates the p-th percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+
+	frac := rank - float64(lower)
+	return sorted[lower] + frac*(sorted[lower+1]-sorted[lower])
+}
+
+// ReadPrompts reads a prompt set from a file with one prompt per line. Blank
+// lines and lines starting with # are skipped.
+func ReadPrompts(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var prompts []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		prompts = append(prompts, line)
+	}
+
+	return prompts, scanner.Err()
+}
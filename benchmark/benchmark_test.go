@@ -0,0 +1,124 @@
+package benchmark
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// approx reports whether a and b are equal up to float rounding.
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{"single sample", []float64{7}, 90, 7},
+		{"median of odd count", []float64{1, 2, 3}, 50, 2},
+		{"median of even count", []float64{1, 2, 3, 4}, 50, 2.5},
+		{"interpolated", []float64{10, 20, 30, 40, 50}, 90, 46},
+		{"minimum", []float64{10, 20, 30}, 0, 10},
+		{"maximum", []float64{10, 20, 30}, 100, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); !approx(got, tt.want) {
+				t.Errorf("percentile(%v, %v) = %v, want %v", tt.sorted, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   Stats
+	}{
+		{"empty", nil, Stats{}},
+		{"single sample", []float64{5}, Stats{Mean: 5, Min: 5, P50: 5, P90: 5, P99: 5, Max: 5}},
+		{
+			"unsorted",
+			[]float64{4, 2, 8, 6},
+			Stats{Mean: 5, StdDev: math.Sqrt(20.0 / 3), Min: 2, P50: 5, P90: 7.4, P99: 7.94, Max: 8},
+		},
+		{"constant", []float64{3, 3, 3}, Stats{Mean: 3, Min: 3, P50: 3, P90: 3, P99: 3, Max: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stats(tt.values)
+			if !approx(got.Mean, tt.want.Mean) ||
+				!approx(got.StdDev, tt.want.StdDev) ||
+				!approx(got.Min, tt.want.Min) ||
+				!approx(got.P50, tt.want.P50) ||
+				!approx(got.P90, tt.want.P90) ||
+				!approx(got.P99, tt.want.P99) ||
+				!approx(got.Max, tt.want.Max) {
+				t.Errorf("stats(%v) = %+v, want %+v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokensPerSecond(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample Sample
+		tps    float64
+		ptps   float64
+	}{
+		{
+			name:   "generation and prompt",
+			sample: Sample{EvalCount: 100, EvalDuration: 2 * time.Second, PromptEvalCount: 30, PromptEvalDuration: 500 * time.Millisecond},
+			tps:    50,
+			ptps:   60,
+		},
+		{
+			// a cached prompt is not evaluated again
+			name:   "no durations",
+			sample: Sample{EvalCount: 10, PromptEvalCount: 30},
+		},
+		{name: "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sample.TokensPerSecond(); !approx(got, tt.tps) {
+				t.Errorf("TokensPerSecond() = %v, want %v", got, tt.tps)
+			}
+			if got := tt.sample.PromptTokensPerSecond(); !approx(got, tt.ptps) {
+				t.Errorf("PromptTokensPerSecond() = %v, want %v", got, tt.ptps)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	samples := []Sample{
+		{EvalCount: 100, EvalDuration: time.Second, LoadDuration: 2 * time.Second, TimeToFirstToken: 300 * time.Millisecond},
+		{EvalCount: 300, EvalDuration: time.Second, TimeToFirstToken: 100 * time.Millisecond},
+	}
+
+	got := Summarize(samples)
+
+	if got.TokensPerSecond.Mean != 200 || got.TokensPerSecond.Min != 100 || got.TokensPerSecond.Max != 300 {
+		t.Errorf("tokens/s = %+v", got.TokensPerSecond)
+	}
+	if got.LoadTime.Mean != 1000 || got.LoadTime.Max != 2000 {
+		t.Errorf("load time = %+v, want milliseconds", got.LoadTime)
+	}
+	if got.TimeToFirstToken.P50 != 200 {
+		t.Errorf("time to first token = %+v", got.TimeToFirstToken)
+	}
+
+	if got := Summarize(nil); got != (Summary{}) {
+		t.Errorf("Summarize(nil) = %+v, want zero stats", got)
+	}
+}
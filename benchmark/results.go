@@ -0,0 +1,79 @@
+package benchmark
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/gaurav-gosain/ollamanager/config"
+)
+
+// Path returns the default results location inside the ollamanager data dir.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "benchmarks.jsonl"), nil
+}
+
+// Save appends the result to the results file at path, creating it if
+// needed.
+func Save(path string, result Result) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(result)
+}
+
+// Read returns every saved result, newest first. A missing file has no
+// results and malformed lines are skipped.
+func Read(path string) ([]Result, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var results []Result
+
+	scanner := bufio.NewScanner(f)
+	// results embed every sample, allow lines longer than the default 64KB
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		var result Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	slices.Reverse(results)
+
+	return results, scanner.Err()
+}
+
+// Latest returns the newest saved result for the model.
+func Latest(results []Result, model string) (Result, bool) {
+	i := slices.IndexFunc(results, func(r Result) bool { return r.Model == model })
+	if i < 0 {
+		return Result{}, false
+	}
+
+	return results[i], true
+}
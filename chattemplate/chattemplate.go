@@ -0,0 +1,118 @@
+// Package chattemplate renders the chat template of a model with sample
+// messages, showing the raw prompt Ollama would send to it.
+package chattemplate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gaurav-gosain/ollamanager/toolcall"
+	"github.com/ollama/ollama/api"
+	"github.com/ollama/ollama/template"
+)
+
+// Sample is a conversation to render a template with.
+type Sample struct {
+	Name     string
+	Messages []api.Message
+	// Tools are sent along with the messages, for templates rendering them.
+	Tools api.Tools
+}
+
+// DefaultSystem is the system message of the samples when the model has no
+// system prompt of its own.
+const DefaultSystem = "You are a helpful assistant."
+
+// Samples returns the conversations offered to render a template with,
+// starting with system (the system prompt of the model, or DefaultSystem).
+func Samples(system string) []Sample {
+	if system == "" {
+		system = DefaultSystem
+	}
+
+	tools, _ := toolcall.Parse([]byte(toolcall.Example))
+
+	return []Sample{
+		{
+			Name: "chat",
+			Messages: []api.Message{
+				{Role: "system", Content: system},
+				{Role: "user", Content: "Hi! What can you do?"},
+				{Role: "assistant", Content: "I can answer questions, write and explain code, and more."},
+				{Role: "user", Content: "Write a haiku about the sea."},
+			},
+		},
+		{
+			Name: "tools",
+			Messages: []api.Message{
+				{Role: "system", Content: system},
+				{Role: "user", Content: "What's the weather in Paris?"},
+				{
+					Role: "assistant",
+					ToolCalls: []api.ToolCall{{
+						Function: api.ToolCallFunction{
+							Name:      "get_current_weather",
+							Arguments: api.ToolCallFunctionArguments{"city": "Paris", "unit": "celsius"},
+						},
+					}},
+				},
+				{Role: "tool", Content: `{"temperature": 18, "conditions": "cloudy"}`},
+			},
+			Tools: tools,
+		},
+		{
+			Name: "prompt",
+			Messages: []api.Message{
+				{Role: "user", Content: "Why is the sky blue?"},
+			},
+		},
+	}
+}
+
+// FindSample returns the sample named name, see Samples.
+func FindSample(name, system string) (Sample, error) {
+	var names []string
+	for _, sample := range Samples(system) {
+		if sample.Name == name {
+			return sample, nil
+		}
+		names = append(names, sample.Name)
+	}
+
+	return Sample{}, fmt.Errorf("unknown sample %q, use one of %s", name, strings.Join(names, ", "))
+}
+
+// Parse checks the template, as Ollama would when creating a model with it.
+// Templates containing """ are rejected too, as the Modelfile quotes them
+// with it and has no way to escape it.
+func Parse(tmpl string) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return errors.New("template cannot be empty")
+	}
+	if strings.Contains(tmpl, `"""`) {
+		return errors.New(`template cannot contain """`)
+	}
+
+	if _, err := template.Parse(tmpl); err != nil {
+		return fmt.Errorf("invalid template: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Render returns the raw prompt the template makes of the sample, the way
+// Ollama renders it before generating the next reply.
+func Render(tmpl string, sample Sample) (string, error) {
+	t, err := template.Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %s", err.Error())
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, template.Values{Messages: sample.Messages, Tools: sample.Tools}); err != nil {
+		return "", fmt.Errorf("failed to render template: %s", err.Error())
+	}
+
+	return b.String(), nil
+}
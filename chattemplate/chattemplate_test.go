@@ -0,0 +1,25 @@
+package chattemplate
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		wantErr bool
+	}{
+		{name: "prompt", tmpl: "{{ .Prompt }}"},
+		{name: "messages", tmpl: "{{ range .Messages }}{{ .Role }}: {{ .Content }}\n{{ end }}"},
+		{name: "empty", tmpl: " \n", wantErr: true},
+		{name: "unclosed action", tmpl: "{{ .Prompt", wantErr: true},
+		{name: "modelfile quotes", tmpl: `{{ .Prompt }}"""`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Parse(tt.tmpl); (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%q) error = %v, want error %t", tt.tmpl, err, tt.wantErr)
+			}
+		})
+	}
+}
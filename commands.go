@@ -0,0 +1,494 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/benchmark"
+	"github.com/gaurav-gosain/ollamanager/genopts"
+	"github.com/gaurav-gosain/ollamanager/history"
+	"github.com/gaurav-gosain/ollamanager/manager"
+	"github.com/gaurav-gosain/ollamanager/metrics"
+	"github.com/ollama/ollama/api"
+)
+
+// runCommand runs a non-interactive subcommand.
+func runCommand(name string, args []string) error {
+	switch name {
+	case "serve-metrics":
+		return serveMetrics(args)
+	case "track-usage":
+		return trackUsage(args)
+	case "create":
+		return create(args)
+	case "export":
+		return export(args)
+	case "import":
+		return importArchive(args)
+	case "verify":
+		return verify(args)
+	case "benchmark":
+		return benchmarkModels(args)
+	case "compare":
+		return compare(args)
+	case "embed":
+		return embed(args)
+	case "chat":
+		return chat(args)
+	case "load":
+		return load(args)
+	case "tools":
+		return toolChat(args)
+	case "schema":
+		return validateSchema(args)
+	case "template":
+		return renderTemplate(args)
+	case "help", "-h", "--help":
+		printUsage()
+		return nil
+	default:
+		printUsage()
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+func printUsage() {
+	fmt.Fprint(os.Stderr, `Usage:
+  ollamanager                  start the interactive model manager
+  ollamanager serve-metrics    expose Ollama model state as Prometheus metrics
+                               (and record model usage)
+  ollamanager track-usage      record when models are loaded, for the last
+                               used times on the Manage tab (-interval)
+  ollamanager create NAME      create a model from a Modelfile (-f), uploading
+                               local GGUF/safetensors files it references
+  ollamanager export MODEL...  bundle models into a tar archive (-o, -zstd) for
+                               offline machines
+  ollamanager import ARCHIVE   verify and unpack an exported archive into the
+                               models directory (-models)
+  ollamanager verify [MODEL...]
+                               re-hash installed blobs and re-pull damaged
+                               models (-repair to skip the prompt)
+  ollamanager benchmark MODEL...
+                               measure tokens/s, time to first token and load
+                               time over a prompt set (-runs, -prompts, -cold)
+  ollamanager compare -p PROMPT MODEL MODEL...
+                               send a prompt to several models at once and
+                               print the comparison as Markdown (-o)
+  ollamanager embed MODEL TEXT...
+                               show the dimensions, norms and cosine
+                               similarities of embeddings (-f, -o)
+  ollamanager chat MODEL       chat with a model in a new saved session, or
+                               continue one (-resume ID)
+  ollamanager load MODEL       keep a model loaded, with the generation
+                               options of a preset (-preset), e.g. its num_ctx
+  ollamanager tools -f TOOLS -p PROMPT MODEL
+                               chat with tool definitions, typing the result of
+                               every tool call the model makes (-preset)
+  ollamanager schema -f SCHEMA -p PROMPT MODEL
+                               send a prompt with a JSON schema format and
+                               report how often the responses conform to it
+                               (-runs, -min, -preset)
+  ollamanager template MODEL   print the raw prompt the chat template of a model
+                               makes of a sample conversation (-sample, -t)
+`)
+}
+
+func serveMetrics(args []string) error {
+	flags := flag.NewFlagSet("serve-metrics", flag.ContinueOnError)
+	addr := flags.String("addr", ":9877", "address to serve /metrics on")
+	interval := flags.Duration("interval", 15*time.Second, "how often to poll the Ollama server")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	client, err := api.ClientFromEnvironment()
+	if err != nil {
+		return err
+	}
+
+	historyPath, err := history.Path()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := sampleUsage(ctx, *interval); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Serving metrics on %s/metrics\n", *addr)
+
+	return metrics.Serve(ctx, *addr, *interval, metrics.NewExporter(client, historyPath))
+}
+
+func trackUsage(args []string) error {
+	flags := flag.NewFlagSet("track-usage", flag.ContinueOnError)
+	interval := flags.Duration("interval", time.Minute, "how often to poll the running models")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := sampleUsage(ctx, *interval); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Recording model usage every %s\n", *interval)
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func create(args []string) error {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	file := flags.String("f", "Modelfile", "path to the Modelfile")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: ollamanager create [-f Modelfile] NAME")
+	}
+
+	modelfile, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	return manager.Create(flags.Arg(0), string(modelfile), filepath.Dir(*file), opts...)
+}
+
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := flags.String("o", "", "path of the archive (default MODEL.tar or MODEL.tar.zst)")
+	compress := flags.Bool("zstd", false, "compress the archive with zstd")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return errors.New("usage: ollamanager export [-o archive] [-zstd] MODEL...")
+	}
+
+	if *output == "" {
+		name := strings.NewReplacer("/", "_", ":", "_").Replace(flags.Arg(0))
+		*output = name + ".tar"
+		if *compress {
+			*output += ".zst"
+		}
+	}
+
+	return manager.Export(flags.Args(), *output, *compress || strings.HasSuffix(*output, ".zst"))
+}
+
+func importArchive(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	dir := flags.String("models", "", "models directory to unpack into (default $OLLAMA_MODELS or ~/.ollama/models)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: ollamanager import [-models dir] ARCHIVE")
+	}
+
+	return manager.Import(flags.Arg(0), *dir)
+}
+
+func verify(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	repair := flags.Bool("repair", false, "re-pull damaged models without asking")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	return manager.Verify(flags.Args(), *repair, opts...)
+}
+
+func benchmarkModels(args []string) error {
+	flags := flag.NewFlagSet("benchmark", flag.ContinueOnError)
+	runs := flags.Int("runs", 3, "how many times to run the prompt set")
+	promptsFile := flags.String("prompts", "", "file with one prompt per line (default the configured prompt set)")
+	cold := flags.Bool("cold", false, "unload the model before every prompt to measure load times")
+	preset := flags.String("preset", "", "generation options preset to benchmark with")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return errors.New("usage: ollamanager benchmark [-runs N] [-prompts file] [-cold] [-preset NAME] MODEL...")
+	}
+
+	modelOptions, err := presetOptions(*preset)
+	if err != nil {
+		return err
+	}
+
+	benchOpts := benchmark.Options{
+		Runs:         *runs,
+		Cold:         *cold,
+		ModelOptions: modelOptions,
+	}
+	if *promptsFile != "" {
+		prompts, err := benchmark.ReadPrompts(*promptsFile)
+		if err != nil {
+			return err
+		}
+		if len(prompts) == 0 {
+			return errors.New("no prompts in " + *promptsFile)
+		}
+		benchOpts.Prompts = prompts
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	return manager.Benchmark(flags.Args(), benchOpts, opts...)
+}
+
+func compare(args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	prompt := flags.String("p", "", "prompt sent to every model")
+	output := flags.String("o", "", "write the Markdown comparison to this file instead of stdout")
+	preset := flags.String("preset", "", "generation options preset sent with the prompt")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *prompt == "" || flags.NArg() < 2 {
+		return errors.New("usage: ollamanager compare -p PROMPT [-o file] [-preset NAME] MODEL MODEL...")
+	}
+
+	modelOptions, err := presetOptions(*preset)
+	if err != nil {
+		return err
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	return manager.Compare(flags.Args(), *prompt, *output, modelOptions, opts...)
+}
+
+func embed(args []string) error {
+	flags := flag.NewFlagSet("embed", flag.ContinueOnError)
+	file := flags.String("f", "", "file with one text per line to embed")
+	output := flags.String("o", "", "write the vectors to this file (.json or .csv)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return errors.New("usage: ollamanager embed [-f file] [-o vectors.json] MODEL [TEXT...]")
+	}
+
+	inputs := flags.Args()[1:]
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				inputs = append(inputs, line)
+			}
+		}
+	}
+
+	if len(inputs) == 0 {
+		return errors.New("nothing to embed, pass texts or -f")
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	return manager.Embed(flags.Arg(0), inputs, *output, opts...)
+}
+
+func chat(args []string) error {
+	flags := flag.NewFlagSet("chat", flag.ContinueOnError)
+	resume := flags.String("resume", "", "ID of the saved session to continue")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if (*resume == "") == (flags.NArg() == 0) {
+		return errors.New("usage: ollamanager chat MODEL | ollamanager chat -resume ID")
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	if *resume != "" {
+		return manager.ResumeChat(*resume, opts...)
+	}
+
+	return manager.Chat(flags.Arg(0), opts...)
+}
+
+func load(args []string) error {
+	flags := flag.NewFlagSet("load", flag.ContinueOnError)
+	preset := flags.String("preset", "", "generation options preset to load the model with")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: ollamanager load [-preset NAME] MODEL")
+	}
+
+	modelOptions, err := presetOptions(*preset)
+	if err != nil {
+		return err
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	return manager.Load(flags.Arg(0), modelOptions, opts...)
+}
+
+func toolChat(args []string) error {
+	flags := flag.NewFlagSet("tools", flag.ContinueOnError)
+	toolsFile := flags.String("f", "", "JSON file with the tool definitions")
+	prompt := flags.String("p", "", "first message of the chat")
+	preset := flags.String("preset", "", "generation options preset for the chat")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *toolsFile == "" || *prompt == "" || flags.NArg() != 1 {
+		return errors.New("usage: ollamanager tools -f tools.json -p PROMPT [-preset NAME] MODEL")
+	}
+
+	modelOptions, err := presetOptions(*preset)
+	if err != nil {
+		return err
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	return manager.ToolChat(flags.Arg(0), *toolsFile, *prompt, modelOptions, opts...)
+}
+
+func validateSchema(args []string) error {
+	flags := flag.NewFlagSet("schema", flag.ContinueOnError)
+	schemaFile := flags.String("f", "", "JSON schema file")
+	prompt := flags.String("p", "", "prompt sent with the schema")
+	runs := flags.Int("runs", 10, "how many times to send the prompt")
+	minRate := flags.Float64("min", 0, "fail when less than this share of the responses conform, from 0 to 1")
+	preset := flags.String("preset", "", "generation options preset sent with the prompt")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *schemaFile == "" || *prompt == "" || flags.NArg() != 1 {
+		return errors.New("usage: ollamanager schema -f schema.json -p PROMPT [-runs N] [-min 0.9] [-preset NAME] MODEL")
+	}
+
+	schema, err := os.ReadFile(*schemaFile)
+	if err != nil {
+		return err
+	}
+
+	modelOptions, err := presetOptions(*preset)
+	if err != nil {
+		return err
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	return manager.ValidateStructured(flags.Arg(0), schema, *prompt, *runs, *minRate, modelOptions, opts...)
+}
+
+func renderTemplate(args []string) error {
+	flags := flag.NewFlagSet("template", flag.ContinueOnError)
+	sample := flags.String("sample", "chat", "sample conversation to render: chat, tools or prompt")
+	templateFile := flags.String("t", "", "template file to render instead of the model's")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: ollamanager template [-sample chat|tools|prompt] [-t FILE] MODEL")
+	}
+
+	var tmpl string
+	if *templateFile != "" {
+		data, err := os.ReadFile(*templateFile)
+		if err != nil {
+			return err
+		}
+		tmpl = string(data)
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		return err
+	}
+
+	prompt, err := manager.RenderTemplate(flags.Arg(0), *sample, tmpl, opts...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(prompt)
+
+	return nil
+}
+
+// presetOptions returns the options of the named preset, or none when name is
+// empty.
+func presetOptions(name string) (genopts.Options, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	return genopts.Preset(name)
+}
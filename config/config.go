@@ -0,0 +1,202 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gaurav-gosain/ollamanager/events"
+)
+
+const appName = "ollamanager"
+
+// Config is the user configuration read from config.json in the ollamanager
+// config directory. Missing files are treated as an empty config.
+type Config struct {
+	// Hooks maps event types to shell commands run when the event fires.
+	Hooks map[events.Type][]string `json:"hooks,omitempty"`
+
+	// Pinned lists the models protected from deletion. They can only be
+	// deleted after typing their name.
+	Pinned []string `json:"pinned,omitempty"`
+
+	// TrashDays enables soft deletion: deleted models are moved to the trash
+	// and purged after this many days. Models are deleted right away when 0.
+	TrashDays int `json:"trash_days,omitempty"`
+
+	// DiskBudget caps the size of the models directory, e.g. "200GB". Pulls
+	// that would exceed it first propose models to evict.
+	DiskBudget string `json:"disk_budget,omitempty"`
+
+	// UnusedDays is how long a model must go without being loaded before the
+	// Manage tab counts it as unused, 30 days by default.
+	UnusedDays int `json:"unused_days,omitempty"`
+
+	// BenchmarkPrompts is the prompt set the Benchmark action runs, a built-in
+	// set is used when empty.
+	BenchmarkPrompts []string `json:"benchmark_prompts,omitempty"`
+
+	// OptionPresets are named sets of generation options, e.g.
+	// {"long-context": {"num_ctx": 32768}}, offered wherever options are set.
+	OptionPresets map[string]map[string]any `json:"option_presets,omitempty"`
+
+	// TimelineMinutes is how far back the Monitor tab charts the loaded
+	// models, 15 minutes by default.
+	TimelineMinutes int `json:"timeline_minutes,omitempty"`
+}
+
+// UnusedAfter returns how long a model must go without being loaded to be
+// counted as unused.
+func (c Config) UnusedAfter() time.Duration {
+	days := c.UnusedDays
+	if days <= 0 {
+		days = 30
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// TimelineWindow returns how far back the Monitor tab charts the loaded
+// models.
+func (c Config) TimelineWindow() time.Duration {
+	minutes := c.TimelineMinutes
+	if minutes <= 0 {
+		minutes = 15
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// DiskBudgetBytes parses DiskBudget, returning 0 when no budget is set.
+func (c Config) DiskBudgetBytes() (int64, error) {
+	if c.DiskBudget == "" {
+		return 0, nil
+	}
+
+	budget, err := humanize.ParseBytes(c.DiskBudget)
+	if err != nil {
+		return 0, errors.New("invalid disk_budget: " + err.Error())
+	}
+
+	return int64(budget), nil
+}
+
+// TrashRetention returns how long deleted models stay in the trash, or 0 when
+// soft deletion is disabled.
+func (c Config) TrashRetention() time.Duration {
+	return time.Duration(c.TrashDays) * 24 * time.Hour
+}
+
+// IsPinned reports whether the model is pinned.
+func (c Config) IsPinned(model string) bool {
+	model = withTag(model)
+	return slices.ContainsFunc(c.Pinned, func(m string) bool { return withTag(m) == model })
+}
+
+// SetPinned pins or unpins the model.
+func (c *Config) SetPinned(model string, pinned bool) {
+	model = withTag(model)
+	c.Pinned = slices.DeleteFunc(c.Pinned, func(m string) bool { return withTag(m) == model })
+	if pinned {
+		c.Pinned = append(c.Pinned, model)
+	}
+}
+
+// withTag adds the implicit latest tag, the form Ollama lists models with.
+func withTag(model string) string {
+	if !strings.Contains(model, ":") {
+		return model + ":latest"
+	}
+
+	return model
+}
+
+// Dir returns the ollamanager config directory, honouring
+// OLLAMANAGER_CONFIG_DIR before falling back to the OS config dir.
+func Dir() (string, error) {
+	if dir := os.Getenv("OLLAMANAGER_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, appName), nil
+}
+
+// DataDir returns the directory used for ollamanager state such as logs and
+// saved sessions, following XDG_DATA_HOME where set.
+func DataDir() (string, error) {
+	if dir := os.Getenv("OLLAMANAGER_DATA_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, appName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".local", "share", appName), nil
+}
+
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "config.json"), nil
+}
+
+func Load() (Config, error) {
+	var cfg Config
+
+	path, err := Path()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, errors.New("invalid config " + path + ": " + err.Error())
+	}
+
+	return cfg, nil
+}
+
+func Save(cfg Config) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,150 @@
+// Package embeddings embeds texts with a model and computes the statistics
+// used to sanity-check embedding models.
+package embeddings
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/ollama/ollama/api"
+)
+
+// Result holds the vector of every input text.
+type Result struct {
+	Model   string
+	Inputs  []string
+	Vectors [][]float32
+}
+
+// Embed embeds the inputs with the model in a single request.
+func Embed(ctx context.Context, client *api.Client, model string, inputs []string) (Result, error) {
+	result := Result{
+		Model:  model,
+		Inputs: inputs,
+	}
+
+	if len(inputs) == 0 {
+		return result, errors.New("nothing to embed")
+	}
+
+	resp, err := client.Embed(ctx, &api.EmbedRequest{
+		Model: model,
+		Input: inputs,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if len(resp.Embeddings) != len(inputs) {
+		return result, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(resp.Embeddings))
+	}
+
+	result.Vectors = resp.Embeddings
+
+	return result, nil
+}
+
+// Dimensions returns the length of the vectors.
+func (r Result) Dimensions() int {
+	if len(r.Vectors) == 0 {
+		return 0
+	}
+	return len(r.Vectors[0])
+}
+
+// Norm returns the euclidean norm of the vector.
+func Norm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// Cosine returns the cosine similarity of two vectors, or 0 when either is
+// all zeros or their lengths differ, e.g. vectors of two different models.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+
+	norms := Norm(a) * Norm(b)
+	if norms == 0 {
+		return 0
+	}
+	return dot / norms
+}
+
+// Similarity returns the pairwise cosine similarity matrix of the vectors.
+func (r Result) Similarity() [][]float64 {
+	matrix := make([][]float64, len(r.Vectors))
+	for i, a := range r.Vectors {
+		matrix[i] = make([]float64, len(r.Vectors))
+		for j, b := range r.Vectors {
+			matrix[i][j] = Cosine(a, b)
+		}
+	}
+	return matrix
+}
+
+// WriteJSON writes the inputs and their vectors as JSON.
+func (r Result) WriteJSON(w io.Writer) error {
+	type embedding struct {
+		Input  string    `json:"input"`
+		Vector []float32 `json:"vector"`
+	}
+
+	out := struct {
+		Model      string      `json:"model"`
+		Dimensions int         `json:"dimensions"`
+		Embeddings []embedding `json:"embeddings"`
+	}{
+		Model:      r.Model,
+		Dimensions: r.Dimensions(),
+	}
+	for i, input := range r.Inputs {
+		out.Embeddings = append(out.Embeddings, embedding{Input: input, Vector: r.Vectors[i]})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// WriteCSV writes a row per input, the text followed by the vector
+// components.
+func (r Result) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"input"}
+	for i := range r.Dimensions() {
+		header = append(header, "d"+strconv.Itoa(i))
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i, input := range r.Inputs {
+		row := []string{input}
+		for _, x := range r.Vectors[i] {
+			row = append(row, strconv.FormatFloat(float64(x), 'g', -1, 32))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
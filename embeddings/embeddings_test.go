@@ -0,0 +1,126 @@
+package embeddings
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// approx reports whether a and b are equal up to float32 rounding.
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestNorm(t *testing.T) {
+	tests := []struct {
+		name string
+		v    []float32
+		want float64
+	}{
+		{"empty", nil, 0},
+		{"zero", []float32{0, 0, 0}, 0},
+		{"pythagorean", []float32{3, 4}, 5},
+		{"negative", []float32{-3, -4}, 5},
+		{"unit", []float32{0.6, 0.8}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Norm(tt.v); !approx(got, tt.want) {
+				t.Errorf("Norm(%v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{"identical", []float32{1, 2, 3}, []float32{1, 2, 3}, 1},
+		{"scaled", []float32{1, 2, 3}, []float32{2, 4, 6}, 1},
+		{"orthogonal", []float32{1, 0}, []float32{0, 1}, 0},
+		{"opposite", []float32{1, 2}, []float32{-1, -2}, -1},
+		{"diagonal", []float32{1, 0}, []float32{1, 1}, 1 / math.Sqrt2},
+		{"zero vector", []float32{0, 0}, []float32{1, 1}, 0},
+		{"both zero", []float32{0, 0}, []float32{0, 0}, 0},
+		{"mismatched lengths", []float32{1, 0}, []float32{1, 0, 5}, 0},
+		{"empty", nil, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cosine(tt.a, tt.b); !approx(got, tt.want) {
+				t.Errorf("Cosine(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+var testResult = Result{
+	Model:   "nomic-embed-text:latest",
+	Inputs:  []string{"cat", "kitten, small", `"dog"`},
+	Vectors: [][]float32{{1, 0}, {1, 1}, {0, 2.5}},
+}
+
+func TestSimilarity(t *testing.T) {
+	got := testResult.Similarity()
+	want := [][]float64{
+		{1, 1 / math.Sqrt2, 0},
+		{1 / math.Sqrt2, 1, 1 / math.Sqrt2},
+		{0, 1 / math.Sqrt2, 1},
+	}
+
+	for i := range want {
+		for j := range want[i] {
+			if !approx(got[i][j], want[i][j]) {
+				t.Fatalf("Similarity() = %v, want %v", got, want)
+			}
+		}
+	}
+
+	if got := (Result{}).Similarity(); len(got) != 0 {
+		t.Errorf("Similarity() of no vectors = %v", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := testResult.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "input,d0,d1\ncat,1,0\n\"kitten, small\",1,1\n\"\"\"dog\"\"\",0,2.5\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := testResult.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Model      string `json:"model"`
+		Dimensions int    `json:"dimensions"`
+		Embeddings []struct {
+			Input  string    `json:"input"`
+			Vector []float32 `json:"vector"`
+		} `json:"embeddings"`
+	}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Model != testResult.Model || got.Dimensions != 2 || len(got.Embeddings) != 3 {
+		t.Fatalf("WriteJSON() = %s", b.String())
+	}
+	if e := got.Embeddings[2]; e.Input != `"dog"` || len(e.Vector) != 2 || e.Vector[1] != 2.5 {
+		t.Errorf("embedding = %+v", e)
+	}
+}
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+type Type string
+
+const (
+	PULL_PROGRESS Type = "pull_progress"
+	PULL_DONE     Type = "pull_done"
+	DELETE        Type = "delete"
+	LOAD          Type = "load"
+	UNLOAD        Type = "unload"
+	CREATE        Type = "create"
+	COPY          Type = "copy"
+	PUSH          Type = "push"
+	ERROR         Type = "error"
+)
+
+// Event describes something ollamanager did to a model. Progress is only set
+// for PULL_PROGRESS events and Err is only set for ERROR events.
+type Event struct {
+	Type     Type
+	Model    string
+	Progress *api.ProgressResponse
+	Err      error
+	Time     time.Time
+}
+
+type Handler func(Event)
+
+// Bus fans out emitted events to every registered handler. A nil *Bus is
+// valid and silently drops all events, so callers never have to check for it.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[Type][]Handler
+	any      []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		handlers: map[Type][]Handler{},
+	}
+}
+
+// On registers a handler for a single event type. Registering on a nil *Bus
+// does nothing, as it drops all events anyway.
+func (b *Bus) On(t Type, h Handler) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[t] = append(b.handlers[t], h)
+}
+
+// OnAny registers a handler that receives every event.
+func (b *Bus) OnAny(h Handler) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.any = append(b.any, h)
+}
+
+// Emit delivers the event synchronously to all matching handlers, in the
+// order they were registered.
+func (b *Bus) Emit(e Event) {
+	if b == nil {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := append([]Handler{}, b.handlers[e.Type]...)
+	handlers = append(handlers, b.any...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(e)
+	}
+}
+
+// Hooks is a convenience for registering typed callbacks. Nil fields are
+// skipped.
+type Hooks struct {
+	OnPullProgress func(model string, progress api.ProgressResponse)
+	OnPullDone     func(model string)
+	OnDelete       func(model string)
+	OnLoad         func(model string)
+	OnUnload       func(model string)
+	OnCreate       func(model string)
+	OnCopy         func(model string)
+	OnPush         func(model string)
+	OnError        func(model string, err error)
+}
+
+// Register attaches every non-nil hook to the bus. A nil bus is left alone.
+func (h Hooks) Register(b *Bus) {
+	if b == nil {
+		return
+	}
+
+	if h.OnPullProgress != nil {
+		b.On(PULL_PROGRESS, func(e Event) { h.OnPullProgress(e.Model, *e.Progress) })
+	}
+	if h.OnPullDone != nil {
+		b.On(PULL_DONE, func(e Event) { h.OnPullDone(e.Model) })
+	}
+	if h.OnDelete != nil {
+		b.On(DELETE, func(e Event) { h.OnDelete(e.Model) })
+	}
+	if h.OnLoad != nil {
+		b.On(LOAD, func(e Event) { h.OnLoad(e.Model) })
+	}
+	if h.OnUnload != nil {
+		b.On(UNLOAD, func(e Event) { h.OnUnload(e.Model) })
+	}
+	if h.OnCreate != nil {
+		b.On(CREATE, func(e Event) { h.OnCreate(e.Model) })
+	}
+	if h.OnCopy != nil {
+		b.On(COPY, func(e Event) { h.OnCopy(e.Model) })
+	}
+	if h.OnPush != nil {
+		b.On(PUSH, func(e Event) { h.OnPush(e.Model) })
+	}
+	if h.OnError != nil {
+		b.On(ERROR, func(e Event) { h.OnError(e.Model, e.Err) })
+	}
+}
@@ -0,0 +1,26 @@
+package events
+
+import "testing"
+
+func TestNilBus(t *testing.T) {
+	var b *Bus
+
+	b.On(DELETE, func(Event) { t.Error("handler called on a nil bus") })
+	b.OnAny(func(Event) { t.Error("handler called on a nil bus") })
+	Hooks{OnDelete: func(string) { t.Error("hook called on a nil bus") }}.Register(b)
+	b.Emit(Event{Type: DELETE, Model: "llama3.2:latest"})
+}
+
+func TestHooksRegister(t *testing.T) {
+	b := NewBus()
+
+	var deleted []string
+	Hooks{OnDelete: func(model string) { deleted = append(deleted, model) }}.Register(b)
+
+	b.Emit(Event{Type: DELETE, Model: "llama3.2:latest"})
+	b.Emit(Event{Type: LOAD, Model: "qwen2.5:14b"})
+
+	if len(deleted) != 1 || deleted[0] != "llama3.2:latest" {
+		t.Fatalf("OnDelete got %v, want [llama3.2:latest]", deleted)
+	}
+}
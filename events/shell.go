@@ -0,0 +1,137 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+)
+
+// Shell runs the configured shell commands for each event type. Commands run
+// in the background so a slow hook never stalls the action emitting the
+// event. Progress events are coalesced: while the command of a progress event
+// runs, only the latest of the following ones is kept and run next.
+type Shell struct {
+	commands map[Type][]string
+	output   io.Writer
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[string]bool
+	pending map[string]*Event
+}
+
+// NewShell returns a Shell running commands, keyed by event type. The output
+// of the commands is discarded, see SetOutput.
+func NewShell(commands map[Type][]string) *Shell {
+	return &Shell{
+		commands: commands,
+		output:   io.Discard,
+		running:  map[string]bool{},
+		pending:  map[string]*Event{},
+	}
+}
+
+// SetOutput sends the output of the commands to w, e.g. a log file. Commands
+// run while the TUI owns the terminal, so w should not be the terminal. w must
+// be safe for concurrent writes, as an *os.File is.
+func (s *Shell) SetOutput(w io.Writer) {
+	s.output = w
+}
+
+// Handle starts the commands of the event type. Event details are passed to
+// the command through the OLLAMANAGER_* environment variables.
+func (s *Shell) Handle(e Event) {
+	for _, command := range s.commands[e.Type] {
+		if e.Type != PULL_PROGRESS {
+			s.start(command, e)
+			continue
+		}
+
+		s.mu.Lock()
+		if s.running[command] {
+			s.pending[command] = &e
+			s.mu.Unlock()
+			continue
+		}
+		s.running[command] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runProgress(command, e)
+		}()
+	}
+}
+
+// Wait blocks until every started command is done, e.g. before exiting.
+func (s *Shell) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Shell) start(command string, e Event) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		runShell(command, e, s.output)
+	}()
+}
+
+// runProgress runs the command for e, then for the latest progress event
+// received meanwhile, until none is pending.
+func (s *Shell) runProgress(command string, e Event) {
+	for {
+		runShell(command, e, s.output)
+
+		s.mu.Lock()
+		next := s.pending[command]
+		delete(s.pending, command)
+		if next == nil {
+			s.running[command] = false
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+
+		e = *next
+	}
+}
+
+// ShellHandler returns a handler that runs the configured shell commands for
+// each event type, see Shell.
+func ShellHandler(commands map[Type][]string) Handler {
+	return NewShell(commands).Handle
+}
+
+func runShell(command string, e Event, output io.Writer) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	cmd.Env = append(os.Environ(),
+		"OLLAMANAGER_EVENT="+string(e.Type),
+		"OLLAMANAGER_MODEL="+e.Model,
+		"OLLAMANAGER_TIME="+e.Time.Format("2006-01-02T15:04:05Z07:00"),
+	)
+	if e.Err != nil {
+		cmd.Env = append(cmd.Env, "OLLAMANAGER_ERROR="+e.Err.Error())
+	}
+	if e.Progress != nil {
+		cmd.Env = append(cmd.Env,
+			"OLLAMANAGER_STATUS="+e.Progress.Status,
+			fmt.Sprintf("OLLAMANAGER_COMPLETED=%d", e.Progress.Completed),
+			fmt.Sprintf("OLLAMANAGER_TOTAL=%d", e.Progress.Total),
+		)
+	}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	// hooks are best effort, a failing hook should never abort the action
+	_ = cmd.Run()
+}
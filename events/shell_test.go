@@ -0,0 +1,85 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+)
+
+func TestShellCoalescesProgress(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	log := filepath.Join(t.TempDir(), "hooks.log")
+	shell := NewShell(map[Type][]string{
+		PULL_PROGRESS: {`sleep 0.2; echo "$OLLAMANAGER_COMPLETED" >> ` + log},
+		PULL_DONE:     {`echo done >> ` + log},
+	})
+
+	for completed := range 100 {
+		shell.Handle(Event{
+			Type:     PULL_PROGRESS,
+			Model:    "llama3.2:latest",
+			Progress: &api.ProgressResponse{Completed: int64(completed)},
+		})
+	}
+	shell.Handle(Event{Type: PULL_DONE, Model: "llama3.2:latest"})
+	shell.Wait()
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Fields(string(data))
+
+	// the first progress event runs, the 98 received meanwhile collapse into
+	// the last one
+	progress := 0
+	for _, line := range lines {
+		if line != "done" {
+			progress++
+		}
+	}
+	if progress != 2 {
+		t.Fatalf("progress hook ran %d times, want 2: %v", progress, lines)
+	}
+	if !strings.Contains(string(data), "99\n") {
+		t.Fatalf("latest progress event was dropped: %v", lines)
+	}
+	if !strings.Contains(string(data), "done\n") {
+		t.Fatalf("done hook did not run: %v", lines)
+	}
+}
+
+func TestShellOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	log, err := os.Create(filepath.Join(t.TempDir(), "hooks.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+
+	shell := NewShell(map[Type][]string{
+		DELETE: {`echo "deleted $OLLAMANAGER_MODEL"; echo oops >&2`},
+	})
+	shell.SetOutput(log)
+
+	shell.Handle(Event{Type: DELETE, Model: "llama3.2:latest"})
+	shell.Wait()
+
+	data, err := os.ReadFile(log.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "deleted llama3.2:latest\noops\n" {
+		t.Fatalf("hooks log = %q, want the stdout and stderr of the hook", got)
+	}
+}
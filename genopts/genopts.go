@@ -0,0 +1,219 @@
+// Package genopts describes the Ollama generation options ollamanager lets
+// users set, e.g. temperature or num_ctx, and the named presets saving them.
+package genopts
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gaurav-gosain/ollamanager/config"
+)
+
+// Options are generation options as sent in the options of a generate or
+// chat request. Unset options keep the model's defaults.
+type Options map[string]any
+
+// Kind is the type of the value of an option.
+type Kind int
+
+const (
+	FLOAT Kind = iota
+	INT
+	// STRINGS is a list, entered comma separated.
+	STRINGS
+)
+
+// Field is an option that can be set.
+type Field struct {
+	Name        string
+	Kind        Kind
+	Description string
+}
+
+// Fields lists the options in the order they are shown.
+var Fields = []Field{
+	{"temperature", FLOAT, "Higher is more creative, lower more deterministic."},
+	{"top_p", FLOAT, "Samples from the tokens making up this probability mass."},
+	{"top_k", INT, "Samples from this many most likely tokens."},
+	{"num_ctx", INT, "Context window size in tokens."},
+	{"num_gpu", INT, "Number of layers offloaded to the GPU."},
+	{"num_thread", INT, "CPU threads used for generation."},
+	{"seed", INT, "Fixed seed for reproducible output."},
+	{"repeat_penalty", FLOAT, "How strongly repetitions are penalized."},
+	{"stop", STRINGS, "Sequences that end the response, comma separated."},
+}
+
+// field returns the field with the given name.
+func field(name string) (Field, bool) {
+	i := slices.IndexFunc(Fields, func(f Field) bool { return f.Name == name })
+	if i < 0 {
+		return Field{}, false
+	}
+	return Fields[i], true
+}
+
+// Parse converts the text entered for an option to its value. Empty text
+// returns nil, leaving the option unset.
+func Parse(name, text string) (any, error) {
+	f, ok := field(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown option %q", name)
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+
+	switch f.Kind {
+	case FLOAT:
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, errors.New(name + " must be a number")
+		}
+		return v, nil
+	case INT:
+		v, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, errors.New(name + " must be a whole number")
+		}
+		return v, nil
+	default:
+		var stops []string
+		for _, stop := range strings.Split(text, ",") {
+			if stop = strings.TrimSpace(stop); stop != "" {
+				stops = append(stops, stop)
+			}
+		}
+		return stops, nil
+	}
+}
+
+// Format returns the text entered for an option value, the reverse of Parse.
+// Values read back from JSON are accepted too.
+func Format(value any) string {
+	if list, ok := strs(value); ok {
+		return strings.Join(list, ", ")
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// strs returns a list value as strings.
+func strs(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []any:
+		list := make([]string, len(v))
+		for i, s := range v {
+			list[i] = fmt.Sprint(s)
+		}
+		return list, true
+	}
+
+	return nil, false
+}
+
+// Set sets the option from its text, removing it when the text is empty.
+func (o Options) Set(name, text string) error {
+	value, err := Parse(name, text)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		delete(o, name)
+	} else {
+		o[name] = value
+	}
+
+	return nil
+}
+
+// String formats the options as "key value" pairs sorted by key, quoting the
+// items of lists.
+func (o Options) String() string {
+	var pairs []string
+	for _, key := range slices.Sorted(maps.Keys(o)) {
+		value := Format(o[key])
+		if list, ok := strs(o[key]); ok {
+			value = fmt.Sprintf("%q", list)
+		}
+		pairs = append(pairs, key+" "+value)
+	}
+
+	return strings.Join(pairs, ", ")
+}
+
+// normalize converts the values read back from JSON to the kind of their
+// option, e.g. the float64 of an INT option to an int. Values that don't fit
+// the kind, and unknown options, are kept as they are.
+func normalize(options map[string]any) Options {
+	normalized := make(Options, len(options))
+	for name, value := range options {
+		if v, err := Parse(name, Format(value)); err == nil && v != nil {
+			value = v
+		}
+		normalized[name] = value
+	}
+
+	return normalized
+}
+
+// Presets returns the saved presets by name.
+func Presets() (map[string]Options, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	presets := make(map[string]Options, len(cfg.OptionPresets))
+	for name, options := range cfg.OptionPresets {
+		presets[name] = normalize(options)
+	}
+
+	return presets, nil
+}
+
+// Preset returns the preset with the given name.
+func Preset(name string) (Options, error) {
+	presets, err := Presets()
+	if err != nil {
+		return nil, err
+	}
+
+	options, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("no preset named %q", name)
+	}
+
+	return options, nil
+}
+
+// SavePreset saves the options under the name, replacing any preset with the
+// same name.
+func SavePreset(name string, options Options) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	if cfg.OptionPresets == nil {
+		cfg.OptionPresets = map[string]map[string]any{}
+	}
+	cfg.OptionPresets[name] = options
+
+	return config.Save(cfg)
+}
@@ -0,0 +1,41 @@
+package genopts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPresetsRestoreKinds(t *testing.T) {
+	t.Setenv("OLLAMANAGER_CONFIG_DIR", t.TempDir())
+
+	saved := Options{
+		"temperature": 0.7,
+		"num_ctx":     4096,
+		"seed":        42,
+		"stop":        []string{"</s>", "User:"},
+	}
+	if err := SavePreset("long", saved); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Preset("long")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, saved) {
+		t.Errorf("Preset() = %#v, want %#v", got, saved)
+	}
+}
+
+func TestNormalizeKeepsUnknownValues(t *testing.T) {
+	got := normalize(map[string]any{
+		"num_ctx":  4096.5,
+		"mirostat": 2.0,
+	})
+
+	want := Options{"num_ctx": 4096.5, "mirostat": 2.0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalize() = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+// Package history keeps an append-only JSONL log of every action ollamanager
+// performed on a model.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/ollama/ollama/envconfig"
+)
+
+type Outcome string
+
+const (
+	SUCCESS   Outcome = "success"
+	FAILURE   Outcome = "failure"
+	CANCELLED Outcome = "cancelled"
+)
+
+// Entry is a single recorded action.
+type Entry struct {
+	Time         time.Time     `json:"time"`
+	Host         string        `json:"host"`
+	User         string        `json:"user"`
+	Action       string        `json:"action"`
+	Model        string        `json:"model"`
+	Source       string        `json:"source,omitempty"`
+	DigestBefore string        `json:"digest_before,omitempty"`
+	DigestAfter  string        `json:"digest_after,omitempty"`
+	Bytes        int64         `json:"bytes,omitempty"`
+	Duration     time.Duration `json:"duration"`
+	Outcome      Outcome       `json:"outcome"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// Path returns the default log location inside the ollamanager data dir.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// NewEntry returns an entry for the action stamped with the current time,
+// Ollama host and OS user.
+func NewEntry(action, model string) Entry {
+	entry := Entry{
+		Time:   time.Now(),
+		Host:   envconfig.Host().String(),
+		Action: action,
+		Model:  model,
+	}
+
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+
+	return entry
+}
+
+// Finish fills in the duration and outcome from the action's error.
+func (e *Entry) Finish(err error) {
+	e.Duration = time.Since(e.Time)
+	e.Outcome = SUCCESS
+	if err != nil {
+		e.Outcome = FAILURE
+		e.Error = err.Error()
+	}
+}
+
+var mu sync.Mutex
+
+// Append writes the entry to the log at path, creating it if needed.
+func Append(path string, entry Entry) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(entry)
+}
+
+// Read returns every entry in the log, newest first. A missing log has no
+// entries and malformed lines are skipped.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	slices.Reverse(entries)
+
+	return entries, scanner.Err()
+}
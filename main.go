@@ -1,28 +1,192 @@
 package main
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
 	"github.com/charmbracelet/huh"
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/gaurav-gosain/ollamanager/events"
+	"github.com/gaurav-gosain/ollamanager/history"
 	"github.com/gaurav-gosain/ollamanager/manager"
 	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/timeline"
+	"github.com/gaurav-gosain/ollamanager/usage"
 	"github.com/gaurav-gosain/ollamanager/utils"
+	"github.com/ollama/ollama/api"
 )
 
+// shellHooks runs the event hooks of the user config in the background, see
+// cliOptions. They are waited for before exiting.
+var shellHooks *events.Shell
+
+// cliOptions wires the user config (event hooks) and the history log into
+// manager calls made by the CLI.
+func cliOptions() ([]manager.Option, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	bus := events.NewBus()
+	if len(cfg.Hooks) > 0 {
+		if shellHooks == nil {
+			shellHooks = events.NewShell(cfg.Hooks)
+			if log, err := openHooksLog(); err == nil {
+				shellHooks.SetOutput(log)
+			}
+		}
+		bus.OnAny(shellHooks.Handle)
+	}
+
+	opts := []manager.Option{
+		manager.WithEvents(bus),
+	}
+	if historyPath, err := history.Path(); err == nil {
+		opts = append(opts, manager.WithHistory(historyPath))
+	}
+
+	return opts, nil
+}
+
+// sampleUsage records the running models in the usage log in the background
+// until the context is cancelled.
+func sampleUsage(ctx context.Context, interval time.Duration) error {
+	client, err := api.ClientFromEnvironment()
+	if err != nil {
+		return err
+	}
+
+	path, err := usage.Path()
+	if err != nil {
+		return err
+	}
+
+	go usage.NewSampler(client, path).Run(ctx, interval)
+
+	return nil
+}
+
+// sampleTimeline samples the running models every interval in the
+// background for the Monitor tab charts, until the context is cancelled.
+func sampleTimeline(ctx context.Context, interval time.Duration) (*timeline.Ring, error) {
+	client, err := api.ClientFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg config.Config
+	if loaded, err := config.Load(); err == nil {
+		cfg = loaded
+	}
+
+	ring := timeline.NewRing(cfg.TimelineWindow(), interval)
+	go ring.Run(ctx, client)
+
+	return ring, nil
+}
+
+// openHooksLog opens hooks.log in the data dir for appending. The output of
+// the hooks goes there, as writing it to the terminal would corrupt the TUI.
+func openHooksLog() (*os.File, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(filepath.Join(dir, "hooks.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
+
+// waitHooks waits for the event hooks still running.
+func waitHooks() {
+	if shellHooks != nil {
+		shellHooks.Wait()
+	}
+}
+
 func main() {
+	defer waitHooks()
+
+	if len(os.Args) > 1 {
+		err := runCommand(os.Args[1], os.Args[2:])
+		waitHooks()
+		if err != nil {
+			utils.PrintError(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	opts, err := cliOptions()
+	if err != nil {
+		utils.PrintError(err)
+		return
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	// usage is only sampled while ollamanager is open, run
+	// `ollamanager track-usage` as a service to sample all the time
+	if err := sampleUsage(ctx, 15*time.Second); err != nil {
+		utils.PrintError(err)
+		return
+	}
+
+	ring, err := sampleTimeline(ctx, 5*time.Second)
+	if err != nil {
+		utils.PrintError(err)
+		return
+	}
+	opts = append(opts, manager.WithTimeline(ring))
+
 	for {
 		selectedTabs := []tabs.Tab{
 			tabs.INSTALL,
 			tabs.MANAGE,
 			tabs.MONITOR,
+			tabs.HISTORY,
+			tabs.SESSIONS,
+		}
+		if cfg, err := config.Load(); err == nil && cfg.TrashDays > 0 {
+			selectedTabs = slices.Insert(selectedTabs, 3, tabs.TRASH)
 		}
 		approvedActions := []tabs.ManageAction{
 			tabs.UPDATE,
 			tabs.DELETE,
-
-			// INFO: Other actions
-			// tabs.CHAT,
+			tabs.CREATE,
+			tabs.IMPORT,
+			tabs.COPY,
+			tabs.PUSH,
+			tabs.PIN,
+			tabs.BENCHMARK,
+			tabs.COMPARE,
+			tabs.EMBED,
+			tabs.LOAD,
+			tabs.TOOLS,
+			tabs.SCHEMA,
+			tabs.TEMPLATE,
+			tabs.CHAT,
 		}
 
-		result, err := manager.Run(selectedTabs, approvedActions)
+		result, err := manager.Run(
+			selectedTabs,
+			approvedActions,
+			opts...,
+		)
+
+		// a host app chats with the picked model itself, run the
+		// built-in chat when standalone
+		if err == nil && result.ManageAction == tabs.CHAT {
+			err = manager.Chat(result.ModelName, opts...)
+		}
 
 		err = utils.PrintActionResult(
 			result,
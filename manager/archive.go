@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/store"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/api"
+)
+
+// Export bundles the manifests and blobs of the models from the local models
+// directory into a tar archive at output, for moving them to machines that
+// cannot pull. The archive is zstd-compressed when compress is set.
+func Export(names []string, output string, compress bool) error {
+	if len(names) == 0 {
+		return errors.New("no models to export")
+	}
+
+	s := store.Open("")
+	for i, name := range names {
+		if err := validateModelName(name); err != nil {
+			return err
+		}
+		if _, err := os.Stat(s.ManifestPath(name)); err != nil {
+			return fmt.Errorf("model %s not found in %s", name, s.Dir)
+		}
+		names[i] = parseModelName(name).String()
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+
+	_, err = runWithProgress(func(fn func(api.ProgressResponse) error) error {
+		if err := s.Export(f, names, compress, fn); err != nil {
+			return err
+		}
+		return fn(api.ProgressResponse{Status: "success"})
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+		return fmt.Errorf("failed to export models: %s", err.Error())
+	}
+
+	printArchiveResult("Exported", names, output)
+
+	return nil
+}
+
+// Import verifies the archive at path and unpacks it into the models
+// directory dir (the Ollama models directory when empty). The Ollama server
+// using that directory lists the models without a restart.
+func Import(path, dir string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := store.Open(dir)
+
+	var names []string
+	_, err = runWithProgress(func(fn func(api.ProgressResponse) error) error {
+		imported, err := s.Import(f, fn)
+		if err != nil {
+			return err
+		}
+		names = imported
+		return fn(api.ProgressResponse{Status: "success"})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import models: %s", err.Error())
+	}
+
+	printArchiveResult("Imported", names, s.Dir)
+
+	return nil
+}
+
+func printArchiveResult(verb string, names []string, location string) {
+	styled := make([]string, len(names))
+	for i, name := range names {
+		styled[i] = tui.StatusStyle.Render(name)
+	}
+
+	fmt.Println(
+		lipgloss.NewStyle().Padding(0, 2).Render(
+			fmt.Sprintln(verb, strings.Join(styled, " "), "-", location),
+		),
+	)
+}
@@ -0,0 +1,232 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/benchmark"
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/gaurav-gosain/ollamanager/genopts"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/envconfig"
+)
+
+// benchmarkPrompts returns the configured prompt set, or the default one.
+func benchmarkPrompts() ([]string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.BenchmarkPrompts) > 0 {
+		return cfg.BenchmarkPrompts, nil
+	}
+
+	return benchmark.DefaultPrompts, nil
+}
+
+// benchmarkForm asks how many times to run the prompt set and whether to
+// measure cold loads.
+func benchmarkForm(modelName string) (opts benchmark.Options, err error) {
+	opts.Prompts, err = benchmarkPrompts()
+	if err != nil {
+		return opts, err
+	}
+
+	opts.ModelOptions, err = generationOptionsForm("Options for the benchmark of " + modelName)
+	if err != nil {
+		return opts, err
+	}
+
+	runs := "3"
+	confirm := false
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Benchmark "+modelName).
+				Description(fmt.Sprintf("How many times to run the %d prompts.", len(opts.Prompts))).
+				Value(&runs).
+				Validate(func(s string) error {
+					n, err := strconv.Atoi(strings.TrimSpace(s))
+					if err != nil || n <= 0 {
+						return errors.New("enter a positive number")
+					}
+					return nil
+				}),
+			huh.NewConfirm().
+				Title("Unload the model before every prompt?").
+				Description("Measures the load time of every prompt instead of only the first one.").
+				Value(&opts.Cold),
+			huh.NewConfirm().
+				Title("Would you like to continue?").
+				Value(&confirm),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err = form.Run(); err != nil {
+		return opts, err
+	}
+
+	if !confirm {
+		return opts, errors.New("see you")
+	}
+
+	opts.Runs, _ = strconv.Atoi(strings.TrimSpace(runs))
+
+	return opts, nil
+}
+
+// benchmarkModel benchmarks the model, prints a report compared to its
+// previous benchmark and saves the result.
+func (o OllamaAPI) benchmarkModel(modelName string, opts benchmark.Options) error {
+	style := lipgloss.NewStyle().Padding(0, 2)
+
+	header := fmt.Sprintf(
+		"Benchmarking %s with %d prompts, %d runs",
+		tui.StatusStyle.Render(modelName),
+		len(opts.Prompts),
+		opts.Runs,
+	)
+	if len(opts.ModelOptions) > 0 {
+		header += " (" + genopts.Options(opts.ModelOptions).String() + ")"
+	}
+	fmt.Println(style.Render(header))
+
+	result, err := benchmark.Run(
+		context.Background(),
+		o.client,
+		modelName,
+		opts,
+		func(done, total int, s benchmark.Sample) {
+			fmt.Println(style.Render(fmt.Sprintf(
+				"[%d/%d] %.1f tokens/s • first token %.0f ms • load %.0f ms",
+				done,
+				total,
+				s.TokensPerSecond(),
+				float64(s.TimeToFirstToken.Milliseconds()),
+				float64(s.LoadDuration.Milliseconds()),
+			)))
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf("failed to benchmark model: %s", err.Error())
+		o.emitError(modelName, err)
+		return err
+	}
+
+	result.Host = envconfig.Host().String()
+	result.Digest = o.digest(modelName)
+
+	path, err := benchmark.Path()
+	if err != nil {
+		return err
+	}
+
+	results, err := benchmark.Read(path)
+	if err != nil {
+		return err
+	}
+	previous, hasPrevious := benchmark.Latest(results, modelName)
+
+	fmt.Println(style.Render(benchmarkReport(result)))
+	if hasPrevious {
+		fmt.Println(style.Render(fmt.Sprintf(
+			"Previous benchmark (%s): %.1f tokens/s mean, %+.1f%% now",
+			previous.Time.Format("2006-01-02 15:04"),
+			previous.Summary.TokensPerSecond.Mean,
+			percentChange(previous.Summary.TokensPerSecond.Mean, result.Summary.TokensPerSecond.Mean),
+		)))
+
+		// results are only comparable with the same options
+		before, now := genopts.Options(previous.Options).String(), genopts.Options(result.Options).String()
+		if before != now {
+			if before == "" {
+				before = "model defaults"
+			}
+			fmt.Println(style.Render("The previous benchmark used different options: " + before))
+		}
+	}
+
+	if err := benchmark.Save(path, result); err != nil {
+		return fmt.Errorf("failed to save benchmark: %s", err.Error())
+	}
+
+	fmt.Println(style.Render(fmt.Sprintln("Results saved to", tui.StatusStyle.Render(path))))
+
+	return nil
+}
+
+// benchmarkReport renders the summary of a result as a table.
+func benchmarkReport(result benchmark.Result) string {
+	var report strings.Builder
+
+	w := tabwriter.NewWriter(&report, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "\tmean\tstddev\tmin\tp50\tp90\tp99\tmax\t")
+
+	rows := []struct {
+		name  string
+		stats benchmark.Stats
+	}{
+		{"tokens/s", result.Summary.TokensPerSecond},
+		{"prompt tokens/s", result.Summary.PromptTokensPerSecond},
+		{"first token (ms)", result.Summary.TimeToFirstToken},
+		{"load (ms)", result.Summary.LoadTime},
+	}
+	for _, row := range rows {
+		s := row.stats
+		fmt.Fprintf(w, "%s\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t\n", row.name, s.Mean, s.StdDev, s.Min, s.P50, s.P90, s.P99, s.Max)
+	}
+	w.Flush()
+
+	return report.String()
+}
+
+func percentChange(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return (after - before) / before * 100
+}
+
+// Benchmark runs the prompt set through every model and saves the results,
+// see benchmarkModel. The configured prompt set is used when benchOpts has
+// none.
+func Benchmark(names []string, benchOpts benchmark.Options, opts ...Option) error {
+	if len(benchOpts.Prompts) == 0 {
+		prompts, err := benchmarkPrompts()
+		if err != nil {
+			return err
+		}
+		benchOpts.Prompts = prompts
+	}
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := validateModelName(name); err != nil {
+			return err
+		}
+		name = parseModelName(name).String()
+
+		entry := ollamaAPI.beginHistory(string(tabs.BENCHMARK), name)
+		err := ollamaAPI.benchmarkModel(name, benchOpts)
+		ollamaAPI.endHistory(entry, 0, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
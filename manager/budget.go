@@ -0,0 +1,265 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/dustin/go-humanize"
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/gaurav-gosain/ollamanager/policy"
+	"github.com/gaurav-gosain/ollamanager/store"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/usage"
+	"github.com/ollama/ollama/api"
+	"github.com/ollama/ollama/envconfig"
+)
+
+// evictionPlan lists the models to delete to make room for a pull. The
+// trash, if any, is emptied first.
+type evictionPlan struct {
+	trash  int64
+	models []evictionCandidate
+	freed  int64
+}
+
+// evictionCandidate is an installed model with the last time it was pulled
+// or loaded.
+type evictionCandidate struct {
+	api.ListModelResponse
+	lastActive time.Time
+}
+
+// ensureDiskBudget makes sure pulling modelName keeps the models directory
+// within the configured disk budget. When it would not, an eviction plan is
+// proposed and the models are deleted once the user accepts it. The budget
+// only applies to a local Ollama server, the disk of a remote one can't be
+// measured.
+func (o OllamaAPI) ensureDiskBudget(modelName string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	budget, err := cfg.DiskBudgetBytes()
+	if err != nil || budget == 0 {
+		return err
+	}
+
+	if host := envconfig.Host(); !isLocalHost(host.Hostname()) {
+		fmt.Println(
+			lipgloss.NewStyle().Padding(0, 2).Render(
+				fmt.Sprintln("The disk budget is not checked, the Ollama server at", host.Host, "is not local"),
+			),
+		)
+		return nil
+	}
+
+	s := store.Open("")
+
+	usage, err := s.Usage()
+	if err != nil {
+		return fmt.Errorf("could not check the disk budget: %s", err.Error())
+	}
+
+	manifest, err := policy.RemoteManifest(modelName)
+	if err != nil {
+		return fmt.Errorf("could not check the download size of %s: %s", modelName, err.Error())
+	}
+
+	// layers already on disk, e.g. shared with an installed model, are not
+	// downloaded again
+	var need int64
+	for _, layer := range manifest.Blobs() {
+		if _, err := os.Stat(s.BlobPath(layer.Digest)); err != nil {
+			need += layer.Size
+		}
+	}
+
+	excess := usage + need - budget
+	if excess <= 0 {
+		return nil
+	}
+
+	plan, err := o.planEviction(s, cfg, modelName, excess)
+	if err != nil {
+		return err
+	}
+
+	if plan.freed < excess {
+		return fmt.Errorf(
+			"pulling %s needs %s, the models directory uses %s of its %s budget and only %s can be evicted",
+			modelName,
+			humanize.Bytes(uint64(need)),
+			humanize.Bytes(uint64(usage)),
+			humanize.Bytes(uint64(budget)),
+			humanize.Bytes(uint64(plan.freed)),
+		)
+	}
+
+	if err := confirmEviction(modelName, plan, need, usage, budget); err != nil {
+		return err
+	}
+
+	if plan.trash > 0 {
+		if err := s.EmptyTrash(); err != nil {
+			return fmt.Errorf("failed to empty the trash: %s", err.Error())
+		}
+	}
+
+	for _, model := range plan.models {
+		entry := o.beginHistory(string(tabs.DELETE), model.Name)
+		err := o.deleteModel(model.Name)
+		o.endHistory(entry, 0, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planEviction empties the trash, then picks the least recently used models
+// until at least excess bytes are freed. Models never seen loaded by the usage
+// sampler count as used when they were pulled. Pinned and running models, and
+// the model being pulled, are never evicted.
+func (o OllamaAPI) planEviction(s store.Store, cfg config.Config, modelName string, excess int64) (evictionPlan, error) {
+	var plan evictionPlan
+
+	trash, err := s.TrashUsage()
+	if err != nil {
+		return plan, err
+	}
+	plan.trash = trash
+	plan.freed = trash
+	if plan.freed >= excess {
+		return plan, nil
+	}
+
+	ctx := context.Background()
+
+	list, err := o.client.List(ctx)
+	if err != nil {
+		return plan, err
+	}
+
+	running, err := o.client.ListRunning(ctx)
+	if err != nil {
+		return plan, err
+	}
+
+	var usageLog usage.Log
+	if path, err := usage.Path(); err == nil {
+		usageLog, _ = usage.Read(path)
+	}
+
+	target := parseModelName(modelName).String()
+
+	var candidates []evictionCandidate
+	for _, model := range list.Models {
+		if model.Name == target ||
+			cfg.IsPinned(model.Name) ||
+			slices.ContainsFunc(running.Models, func(r api.ProcessModelResponse) bool { return r.Name == model.Name }) {
+			continue
+		}
+
+		lastActive := model.ModifiedAt
+		if lastUsed := usageLog.Models[model.Name].LastUsed; lastUsed.After(lastActive) {
+			lastActive = lastUsed
+		}
+
+		candidates = append(candidates, evictionCandidate{
+			ListModelResponse: model,
+			lastActive:        lastActive,
+		})
+	}
+
+	slices.SortFunc(candidates, func(a, b evictionCandidate) int {
+		return a.lastActive.Compare(b.lastActive)
+	})
+
+	reclaimer, err := s.NewReclaimer()
+	if err != nil {
+		return plan, err
+	}
+
+	for _, model := range candidates {
+		plan.models = append(plan.models, model)
+
+		// blobs shared with models that are kept are not freed
+		reclaimed, err := reclaimer.Add(model.Name)
+		if err != nil {
+			return plan, err
+		}
+		plan.freed = plan.trash + reclaimed
+
+		if plan.freed >= excess {
+			break
+		}
+	}
+
+	return plan, nil
+}
+
+// confirmEviction shows the eviction plan and asks the user to accept it. It
+// fails without a terminal to ask on.
+func confirmEviction(modelName string, plan evictionPlan, need, usage, budget int64) error {
+	lines := []string{
+		fmt.Sprintf(
+			"Pulling it needs %s, the models directory uses %s of its %s budget.",
+			humanize.Bytes(uint64(need)),
+			humanize.Bytes(uint64(usage)),
+			humanize.Bytes(uint64(budget)),
+		),
+	}
+	if plan.trash > 0 {
+		lines = append(lines, "", "Empty the trash ("+humanize.Bytes(uint64(plan.trash))+").")
+	}
+	if len(plan.models) > 0 {
+		lines = append(lines, "", "Least recently used models to delete:")
+	}
+	for _, model := range plan.models {
+		lines = append(lines, fmt.Sprintf(
+			"  %s (%s, last used %s)",
+			model.Name,
+			humanize.Bytes(uint64(model.Size)),
+			humanize.Time(model.lastActive),
+		))
+	}
+	lines = append(lines, "", "This frees "+humanize.Bytes(uint64(plan.freed))+".")
+
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return errors.New("disk budget exceeded, run interactively to accept the eviction plan:\n" + strings.Join(lines, "\n"))
+	}
+
+	confirm := false
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(modelName + " does not fit in the disk budget").
+				Description(strings.Join(lines, "\n")).
+				Affirmative("Evict and pull").
+				Negative("Cancel").
+				Value(&confirm),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return err
+	}
+
+	if !confirm {
+		return errors.New("see you")
+	}
+
+	return nil
+}
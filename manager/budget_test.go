@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gaurav-gosain/ollamanager/config"
+)
+
+func TestPlanEvictionEmptiesTrashFirst(t *testing.T) {
+	o, _, _ := newTestAPI(t)
+	s := newTestStore(t, "llama3.2")
+
+	trashed := filepath.Join(s.TrashDir(), "1", "blobs", "sha256-0")
+	if err := os.MkdirAll(filepath.Dir(trashed), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(trashed, make([]byte, 1000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := o.planEviction(s, config.Config{}, "qwen2.5:7b", 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.trash != 1000 || plan.freed != 1000 || len(plan.models) != 0 {
+		t.Fatalf("plan = %+v, want only the trash emptied", plan)
+	}
+
+	plan, err = o.planEviction(s, config.Config{}, "qwen2.5:7b", 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.trash != 1000 || len(plan.models) != 1 || plan.models[0].Name != "llama3.2:latest" {
+		t.Fatalf("plan = %+v, want the trash and llama3.2 evicted", plan)
+	}
+}
+
+func TestDiskBudgetSkipsRemoteServers(t *testing.T) {
+	o, _, _ := newTestAPI(t)
+	t.Setenv("OLLAMA_HOST", "http://gpu-box:11434")
+	// a local models dir that is already over budget must not matter
+	newTestStore(t, "llama3.2")
+
+	if err := config.Save(config.Config{DiskBudget: "1B"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.ensureDiskBudget("qwen2.5:7b"); err != nil {
+		t.Fatalf("ensureDiskBudget() = %v, want the budget skipped", err)
+	}
+}
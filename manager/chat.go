@@ -0,0 +1,190 @@
+package manager
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/session"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/gaurav-gosain/ollamanager/utils"
+	"github.com/ollama/ollama/api"
+)
+
+// newSessionForm asks for the name, system prompt and generation options of a
+// new chat session with the model.
+func newSessionForm(modelName string) (session.Session, error) {
+	name := modelName + " " + time.Now().Format("2006-01-02 15:04")
+	var system string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Chat with "+modelName).
+				Description("Name of the session, to find it on the Sessions tab.").
+				Value(&name).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("name cannot be empty")
+					}
+					return nil
+				}),
+			huh.NewText().
+				Title("System prompt").
+				Description("Leave empty to use the model's own.").
+				Value(&system),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return session.Session{}, err
+	}
+
+	options, err := generationOptionsForm("Options for the chat with " + modelName)
+	if err != nil {
+		return session.Session{}, err
+	}
+
+	s := session.New(modelName, strings.TrimSpace(name))
+	s.System = strings.TrimSpace(system)
+	s.Options = options
+
+	return s, nil
+}
+
+// chat runs a line based conversation in the terminal, saving the session
+// after every reply. It ends on /exit or at the end of the input.
+func (o OllamaAPI) chat(s *session.Session) error {
+	style := lipgloss.NewStyle().Padding(0, 2)
+	you := tui.StatusStyle.Render("you")
+	model := tui.StatusStyle.Render(s.Model)
+
+	header := "Chatting with " + model + " in " + tui.StatusStyle.Render(s.Name)
+	if options := s.OptionsText(); options != "" {
+		header += " (" + options + ")"
+	}
+	fmt.Println(style.Render(header))
+	fmt.Println(style.Render("Type /exit or press ctrl+d to leave, ctrl+c stops a reply."))
+
+	for _, message := range s.Messages {
+		prefix := you
+		if message.Role == "assistant" {
+			prefix = model
+		}
+		fmt.Printf("\n%s %s\n", prefix, message.Content)
+	}
+
+	input := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("\n%s ", you)
+
+		prompt, ok := readLine(input)
+		if !ok {
+			return nil
+		}
+		if prompt == "" {
+			continue
+		}
+
+		s.Messages = append(s.Messages, api.Message{Role: "user", Content: prompt})
+
+		fmt.Printf("\n%s ", model)
+		reply, chatErr := o.chatReply(s)
+		fmt.Println()
+
+		if reply != "" {
+			s.Messages = append(s.Messages, api.Message{Role: "assistant", Content: reply})
+		} else {
+			// nothing to answer to, don't keep the prompt either
+			s.Messages = s.Messages[:len(s.Messages)-1]
+		}
+
+		if err := session.Save(s); err != nil {
+			return fmt.Errorf("failed to save session: %s", err.Error())
+		}
+
+		if chatErr != nil && !errors.Is(chatErr, context.Canceled) {
+			o.emitError(s.Model, chatErr)
+			return fmt.Errorf("failed to chat: %s", chatErr.Error())
+		}
+	}
+}
+
+// readLine reads a trimmed line of input. It reports false on /exit, /quit or
+// at the end of the input.
+func readLine(input *bufio.Reader) (string, bool) {
+	line, err := input.ReadString('\n')
+	line = strings.TrimSpace(line)
+
+	if (err != nil && line == "") || line == "/exit" || line == "/quit" {
+		fmt.Println()
+		return "", false
+	}
+
+	return line, true
+}
+
+// chatReply streams the reply to the conversation to stdout. Interrupting
+// stops the reply, keeping what was received.
+func (o OllamaAPI) chatReply(s *session.Session) (string, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var reply strings.Builder
+
+	stream := true
+	req := &api.ChatRequest{
+		Model:    s.Model,
+		Messages: s.ChatMessages(),
+		Options:  s.Options,
+		Stream:   &stream,
+	}
+
+	err := o.client.Chat(ctx, req, func(resp api.ChatResponse) error {
+		fmt.Print(resp.Message.Content)
+		reply.WriteString(resp.Message.Content)
+		return nil
+	})
+
+	return reply.String(), err
+}
+
+// Chat starts a new chat session with the model, see chat.
+func Chat(modelName string, opts ...Option) error {
+	if err := validateModelName(modelName); err != nil {
+		return err
+	}
+	modelName = parseModelName(modelName).String()
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	s, err := newSessionForm(modelName)
+	if err != nil {
+		return err
+	}
+
+	utils.ClearTerminal()
+
+	return ollamaAPI.chat(&s)
+}
+
+// ResumeChat continues the saved session with the given ID.
+func ResumeChat(id string, opts ...Option) error {
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	return ollamaAPI.manageSession(id, "resume", "")
+}
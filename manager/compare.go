@@ -0,0 +1,251 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/benchmark"
+	"github.com/gaurav-gosain/ollamanager/genopts"
+	"github.com/gaurav-gosain/ollamanager/tui"
+)
+
+// compareForm asks which installed models to compare with modelName, the
+// prompt to send them and the generation options.
+func (o OllamaAPI) compareForm(modelName string) (models []string, prompt string, options genopts.Options, err error) {
+	list, err := o.client.List(context.Background())
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var modelOptions []huh.Option[string]
+	for _, model := range list.Models {
+		modelOptions = append(modelOptions, huh.NewOption(model.Name, model.Name).Selected(model.Name == modelName))
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Models to compare").
+				Options(modelOptions...).
+				Value(&models).
+				Validate(func(selected []string) error {
+					if len(selected) < 2 {
+						return errors.New("select at least two models")
+					}
+					return nil
+				}),
+			huh.NewText().
+				Title("Prompt").
+				Description("Sent to every model at the same time.").
+				Value(&prompt).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("prompt cannot be empty")
+					}
+					return nil
+				}),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err = form.Run(); err != nil {
+		return nil, "", nil, err
+	}
+
+	options, err = generationOptionsForm("Options sent to every model")
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return models, prompt, options, nil
+}
+
+// runComparison sends the prompt to every model concurrently and reports the
+// streamed responses as tui.CompareChunkMsg and tui.CompareDoneMsg to send.
+// It returns once every model is done.
+func (o OllamaAPI) runComparison(ctx context.Context, models []string, prompt string, options genopts.Options, send func(tea.Msg)) {
+	var wg sync.WaitGroup
+	for i, model := range models {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sample, err := benchmark.Generate(ctx, o.client, model, prompt, options, func(chunk string) {
+				send(tui.CompareChunkMsg{Index: i, Chunk: chunk})
+			})
+			if err != nil {
+				o.emitError(model, err)
+			}
+			send(tui.CompareDoneMsg{Index: i, Sample: sample, Err: err})
+		}()
+	}
+	wg.Wait()
+}
+
+// compareModels shows the responses of the models side by side, then offers
+// to export the comparison.
+func (o OllamaAPI) compareModels(models []string, prompt string, options genopts.Options) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	comparison := tui.NewCompareModel(prompt, models)
+	comparison.Options = options
+
+	p := tea.NewProgram(comparison, tea.WithAltScreen())
+
+	go o.runComparison(ctx, models, prompt, options, p.Send)
+
+	res, err := p.Run()
+	// stop the responses still streaming when the user quits early
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return exportComparisonForm(res.(tui.CompareModel))
+}
+
+// exportComparisonForm asks whether to save the comparison as Markdown.
+func exportComparisonForm(comparison tui.CompareModel) error {
+	export := false
+	path := "compare-" + time.Now().Format("20060102-150405") + ".md"
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Export the comparison as Markdown?").
+				Value(&export),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("File").
+				Value(&path).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("file cannot be empty")
+					}
+					return nil
+				}),
+		).WithHideFunc(func() bool { return !export }),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return err
+	}
+
+	if !export {
+		return nil
+	}
+
+	path = strings.TrimSpace(path)
+	if err := os.WriteFile(path, []byte(comparisonMarkdown(comparison)), 0o644); err != nil {
+		return fmt.Errorf("failed to export comparison: %s", err.Error())
+	}
+
+	fmt.Println(
+		lipgloss.NewStyle().Padding(0, 2).Render(
+			fmt.Sprintln("Comparison exported to", tui.StatusStyle.Render(path)),
+		),
+	)
+
+	return nil
+}
+
+// comparisonMarkdown renders the prompt, a stats table and every response.
+func comparisonMarkdown(comparison tui.CompareModel) string {
+	var md strings.Builder
+
+	fmt.Fprintf(&md, "# Model comparison\n\n")
+	fmt.Fprintf(&md, "## Prompt\n\n")
+	for _, line := range strings.Split(strings.TrimSpace(comparison.Prompt), "\n") {
+		fmt.Fprintf(&md, "> %s\n", line)
+	}
+	if len(comparison.Options) > 0 {
+		fmt.Fprintf(&md, "\nOptions: %s\n", genopts.Options(comparison.Options))
+	}
+
+	fmt.Fprintf(&md, "\n| Model | First token | Tokens/s | Tokens | Load | Total |\n")
+	fmt.Fprintf(&md, "| --- | ---: | ---: | ---: | ---: | ---: |\n")
+	for _, result := range comparison.Results {
+		if result.Err != nil || !result.Done {
+			fmt.Fprintf(&md, "| %s | - | - | - | - | - |\n", result.Model)
+			continue
+		}
+
+		s := result.Sample
+		fmt.Fprintf(
+			&md,
+			"| %s | %d ms | %.1f | %d | %d ms | %s |\n",
+			result.Model,
+			s.TimeToFirstToken.Milliseconds(),
+			s.TokensPerSecond(),
+			s.EvalCount,
+			s.LoadDuration.Milliseconds(),
+			s.TotalDuration.Round(100*time.Millisecond),
+		)
+	}
+
+	for _, result := range comparison.Results {
+		fmt.Fprintf(&md, "\n## %s\n\n", result.Model)
+		switch {
+		case result.Err != nil:
+			fmt.Fprintf(&md, "**Error:** %s\n", result.Err.Error())
+		case !result.Done:
+			fmt.Fprintf(&md, "%s\n\n*The response was interrupted.*\n", strings.TrimSpace(result.Output))
+		default:
+			fmt.Fprintf(&md, "%s\n", strings.TrimSpace(result.Output))
+		}
+	}
+
+	return md.String()
+}
+
+// Compare sends the prompt with the generation options to every model
+// concurrently and writes the comparison as Markdown to output, or to stdout
+// when output is empty.
+func Compare(names []string, prompt, output string, options genopts.Options, opts ...Option) error {
+	if len(names) < 2 {
+		return errors.New("compare needs at least two models")
+	}
+	if strings.TrimSpace(prompt) == "" {
+		return errors.New("prompt cannot be empty")
+	}
+
+	for i, name := range names {
+		if err := validateModelName(name); err != nil {
+			return err
+		}
+		names[i] = parseModelName(name).String()
+	}
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	comparison := tui.NewCompareModel(prompt, names)
+	comparison.Options = options
+
+	var mu sync.Mutex
+	ollamaAPI.runComparison(context.Background(), names, prompt, options, func(msg tea.Msg) {
+		mu.Lock()
+		defer mu.Unlock()
+		comparison.Apply(msg)
+	})
+
+	md := comparisonMarkdown(comparison)
+	if output == "" {
+		fmt.Print(md)
+		return nil
+	}
+
+	return os.WriteFile(output, []byte(md), 0o644)
+}
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/benchmark"
+	"github.com/gaurav-gosain/ollamanager/tui"
+)
+
+func TestComparisonMarkdown(t *testing.T) {
+	comparison := tui.NewCompareModel("Why is the sky blue?\nAnswer briefly.", []string{"llama3.2:latest", "qwen2.5:7b", "mistral:latest"})
+	comparison.Options = map[string]any{"temperature": 0.2}
+	comparison.Results[0].Output = "Rayleigh scattering.\n"
+	comparison.Results[0].Done = true
+	comparison.Results[0].Sample = benchmark.Sample{
+		TimeToFirstToken: 120 * time.Millisecond,
+		EvalCount:        40,
+		EvalDuration:     time.Second,
+		LoadDuration:     800 * time.Millisecond,
+		TotalDuration:    2040 * time.Millisecond,
+	}
+	comparison.Results[1].Output = "Because of"
+	comparison.Results[2].Err = errors.New("model not found")
+	comparison.Results[2].Done = true
+
+	want := `# Model comparison
+
+## Prompt
+
+> Why is the sky blue?
+> Answer briefly.
+
+Options: temperature 0.2
+
+| Model | First token | Tokens/s | Tokens | Load | Total |
+| --- | ---: | ---: | ---: | ---: | ---: |
+| llama3.2:latest | 120 ms | 40.0 | 40 | 800 ms | 2s |
+| qwen2.5:7b | - | - | - | - | - |
+| mistral:latest | - | - | - | - | - |
+
+## llama3.2:latest
+
+Rayleigh scattering.
+
+## qwen2.5:7b
+
+Because of
+
+*The response was interrupted.*
+
+## mistral:latest
+
+**Error:** model not found
+`
+
+	if got := comparisonMarkdown(comparison); got != want {
+		t.Errorf("comparisonMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/events"
+	"github.com/gaurav-gosain/ollamanager/history"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/api"
+)
+
+// copyModel copies (or re-points) destination to the model source.
+func (o OllamaAPI) copyModel(source, destination string) error {
+	ctx := context.Background()
+
+	req := &api.CopyRequest{
+		Source:      source,
+		Destination: destination,
+	}
+
+	err := o.client.Copy(ctx, req)
+	if err != nil {
+		err = fmt.Errorf("failed to copy model: %s", err.Error())
+		o.emitError(destination, err)
+		return err
+	}
+
+	o.emit(events.COPY, destination)
+
+	fmt.Println(
+		lipgloss.NewStyle().Padding(0, 2).Render(
+			fmt.Sprintln(
+				"Model",
+				tui.StatusStyle.Render(destination),
+				"now points to",
+				tui.StatusStyle.Render(source),
+			),
+		),
+	)
+
+	return nil
+}
+
+// aliasesOf returns the installed models that can be retargeted to source:
+// the ones created by copying a model (see copiedModels) and the ones already
+// pointing at the same manifest as source. Tags of pulled or created models
+// are never offered, retargeting them would replace a real model. source
+// itself is left out.
+func aliasesOf(models []api.ListModelResponse, copied map[string]bool, source string) []string {
+	source = parseModelName(source).String()
+
+	var sourceDigest string
+	for _, model := range models {
+		if parseModelName(model.Name).String() == source {
+			sourceDigest = model.Digest
+		}
+	}
+
+	var aliases []string
+	for _, model := range models {
+		name := parseModelName(model.Name).String()
+		if name == source {
+			continue
+		}
+		if copied[name] || (sourceDigest != "" && model.Digest == sourceDigest) {
+			aliases = append(aliases, model.Name)
+		}
+	}
+
+	return aliases
+}
+
+// copiedModels returns the models whose last successful action in the
+// history log is a copy, by normalized name. Without a history log no model
+// is known to be a copy.
+func (o OllamaAPI) copiedModels() map[string]bool {
+	copied := map[string]bool{}
+	if o.historyPath == "" {
+		return copied
+	}
+
+	entries, err := history.Read(o.historyPath)
+	if err != nil {
+		return copied
+	}
+
+	// entries are newest first, the first successful one of a model decides
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		name := parseModelName(entry.Model).String()
+		if entry.Outcome != history.SUCCESS || seen[name] {
+			continue
+		}
+		seen[name] = true
+		copied[name] = entry.Action == string(tabs.COPY)
+	}
+
+	return copied
+}
+
+// validateAlias checks that name is a valid name for a new alias of source,
+// one that no installed model uses yet.
+func validateAlias(models []api.ListModelResponse, source string) func(string) error {
+	return func(name string) error {
+		if err := validateModelName(name); err != nil {
+			return err
+		}
+
+		name = parseModelName(name).String()
+		if name == parseModelName(source).String() {
+			return errors.New("alias must differ from the source model")
+		}
+		for _, model := range models {
+			if parseModelName(model.Name).String() == name {
+				return fmt.Errorf("%s is already installed, retarget it instead", model.Name)
+			}
+		}
+
+		return nil
+	}
+}
+
+// copyForm asks whether to create a new alias for source or to retarget an
+// existing one, and returns the destination name.
+func (o OllamaAPI) copyForm(source string) (destination string, err error) {
+	list, err := o.client.List(context.Background())
+	if err != nil {
+		return "", err
+	}
+
+	var aliases []huh.Option[string]
+	for _, alias := range aliasesOf(list.Models, o.copiedModels(), source) {
+		aliases = append(aliases, huh.NewOption(alias, alias))
+	}
+
+	mode := "new"
+	if len(aliases) > 0 {
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title("Copy "+source).
+					Options(
+						huh.NewOption("Create a new alias", "new"),
+						huh.NewOption("Retarget an existing alias to this model", "retarget"),
+					).
+					Value(&mode),
+			),
+		).WithProgramOptions(oldtea.WithAltScreen())
+
+		if err = form.Run(); err != nil {
+			return "", err
+		}
+	}
+
+	confirm := false
+
+	var field huh.Field
+	if mode == "retarget" {
+		field = huh.NewSelect[string]().
+			Title("Alias to point at " + source).
+			Description("The model it currently points to is kept.").
+			Options(aliases...).
+			Value(&destination)
+	} else {
+		field = huh.NewInput().
+			Title("Name of the alias").
+			Placeholder("team-model:latest").
+			Validate(validateAlias(list.Models, source)).
+			Value(&destination)
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			field,
+			huh.NewConfirm().
+				Title("Would you like to continue?").
+				Value(&confirm),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err = form.Run(); err != nil {
+		return "", err
+	}
+
+	if !confirm {
+		return "", errors.New("see you")
+	}
+
+	// retargeting overwrites the alias, which may be pinned
+	if err := forcePinned(destination, "overwrite"); err != nil {
+		return "", err
+	}
+
+	return destination, nil
+}
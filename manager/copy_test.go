@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/gaurav-gosain/ollamanager/history"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/ollama/ollama/api"
+)
+
+var copyModels = []api.ListModelResponse{
+	{Name: "llama3.2:latest", Digest: "a"},
+	{Name: "team-model:latest", Digest: "a"},
+	{Name: "qwen2.5:7b", Digest: "b"},
+	{Name: "old-alias:latest", Digest: "c"},
+	{Name: "qwen2.5:14b", Digest: "c"},
+}
+
+func TestAliasesOf(t *testing.T) {
+	copied := map[string]bool{"team-model:latest": true, "old-alias:latest": true}
+
+	tests := []struct {
+		source string
+		want   []string
+	}{
+		// only copies are offered, not the pulled models they share a
+		// manifest with
+		{"qwen2.5:7b", []string{"team-model:latest", "old-alias:latest"}},
+		// models already pointing at the source are offered, the source
+		// itself never is
+		{"llama3.2", []string{"team-model:latest", "old-alias:latest"}},
+		{"qwen2.5:14b", []string{"team-model:latest", "old-alias:latest"}},
+		{"team-model", []string{"llama3.2:latest", "old-alias:latest"}},
+	}
+
+	for _, tt := range tests {
+		if got := aliasesOf(copyModels, copied, tt.source); !slices.Equal(got, tt.want) {
+			t.Errorf("aliasesOf(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+
+	if got := aliasesOf(copyModels, nil, "qwen2.5:7b"); len(got) != 0 {
+		t.Errorf("aliasesOf without copies = %v, want none", got)
+	}
+}
+
+func TestCopiedModels(t *testing.T) {
+	o, _, _ := newTestAPI(t)
+	o.historyPath = filepath.Join(t.TempDir(), "history.jsonl")
+
+	for _, entry := range []history.Entry{
+		{Action: string(tabs.COPY), Model: "team-model", Outcome: history.SUCCESS},
+		{Action: string(tabs.INSTALL), Model: "llama3.2", Outcome: history.SUCCESS},
+		// pulled over a copy, it is a real model again
+		{Action: string(tabs.COPY), Model: "qwen2.5:14b", Outcome: history.SUCCESS},
+		{Action: string(tabs.UPDATE), Model: "qwen2.5:14b", Outcome: history.SUCCESS},
+		// failed copies don't count
+		{Action: string(tabs.COPY), Model: "llama3.2", Outcome: history.FAILURE},
+	} {
+		if err := history.Append(o.historyPath, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := o.copiedModels()
+	want := map[string]bool{"team-model:latest": true, "llama3.2:latest": false, "qwen2.5:14b": false}
+	if !maps.Equal(got, want) {
+		t.Fatalf("copiedModels() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateAlias(t *testing.T) {
+	validate := validateAlias(copyModels, "qwen2.5:7b")
+
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"new-alias", true},
+		{"qwen2.5:7b", false},
+		{"team-model", false},
+		{"team-model:latest", false},
+		{"llama3.2", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if err := validate(tt.name); (err == nil) != tt.ok {
+			t.Errorf("validate(%q) = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
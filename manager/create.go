@@ -0,0 +1,190 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/progress"
+	oldtea "github.com/charmbracelet/bubbletea"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/x/term"
+	"github.com/gaurav-gosain/ollamanager/events"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/api"
+)
+
+// runWithProgress runs action in the background and shows the progress it
+// reports in the install view. When stdout is not a terminal (e.g. in CI) the
+// progress is printed line by line instead. It returns the number of bytes
+// reported as transferred.
+func runWithProgress(action func(fn func(api.ProgressResponse) error) error) (int64, error) {
+	if !term.IsTerminal(os.Stdout.Fd()) {
+		var lastStatus string
+		layers := map[string]int64{}
+		err := action(func(resp api.ProgressResponse) error {
+			if resp.Digest != "" {
+				layers[resp.Digest] = resp.Completed
+			}
+			if resp.Status != lastStatus {
+				fmt.Println(resp.Status)
+				lastStatus = resp.Status
+			}
+			return nil
+		})
+
+		var transferred int64
+		for _, completed := range layers {
+			transferred += completed
+		}
+
+		return transferred, err
+	}
+
+	m := tui.InstallModel{
+		Progress: progress.New(progress.WithDefaultGradient()),
+		Spinner:  tui.InitSpinner(),
+	}
+	p := tea.NewProgram(m, tea.WithFerociousRenderer())
+
+	go func() {
+		err := action(func(resp api.ProgressResponse) error {
+			p.Send(resp)
+			return nil
+		})
+		if err != nil {
+			p.Send(tui.ProgressError(err))
+		}
+	}()
+
+	res, err := p.Run()
+	if err != nil {
+		return 0, fmt.Errorf("error running program: %s", err.Error())
+	}
+
+	return res.(tui.InstallModel).Transferred(), res.(tui.InstallModel).Err
+}
+
+// createModel creates modelName from the Modelfile contents, streaming the
+// status into the install view. Local files referenced by the Modelfile are
+// uploaded first, relative paths are resolved against dir.
+func (o OllamaAPI) createModel(modelName, modelfile, dir string) error {
+	ctx := context.Background()
+
+	_, err := runWithProgress(func(fn func(api.ProgressResponse) error) error {
+		resolved, err := o.resolveModelfile(modelfile, dir, fn)
+		if err != nil {
+			return err
+		}
+
+		req := &api.CreateRequest{
+			Model:     modelName,
+			Modelfile: resolved,
+		}
+
+		return o.client.Create(ctx, req, fn)
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to create model: %s", err.Error())
+		o.emitError(modelName, err)
+		return err
+	}
+
+	o.emit(events.CREATE, modelName)
+
+	return nil
+}
+
+// modelfileFromShow rebuilds an editable Modelfile deriving from modelName,
+// keeping its parameters, system prompt and template.
+func modelfileFromShow(modelName string, show *api.ShowResponse) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "FROM %s\n", modelName)
+
+	for _, line := range strings.Split(show.Parameters, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		fmt.Fprintf(&b, "PARAMETER %s %s\n", fields[0], strings.Join(fields[1:], " "))
+	}
+
+	if show.System != "" {
+		fmt.Fprintf(&b, "SYSTEM \"\"\"%s\"\"\"\n", show.System)
+	}
+
+	if show.Template != "" {
+		fmt.Fprintf(&b, "TEMPLATE \"\"\"%s\"\"\"\n", show.Template)
+	}
+
+	return b.String()
+}
+
+// editModelfile asks for the new model name and lets the user edit a
+// Modelfile pre-filled from the base model.
+func (o OllamaAPI) editModelfile(baseModel string) (modelName, modelfile string, err error) {
+	show, err := o.client.Show(context.Background(), &api.ShowRequest{Model: baseModel})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to show model: %s", err.Error())
+	}
+
+	modelfile = modelfileFromShow(baseModel, show)
+
+	confirm := false
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Name of the new model").
+				Placeholder("my-"+strings.Split(baseModel, ":")[0]).
+				Validate(validateModelName).
+				Value(&modelName),
+			huh.NewText().
+				Title("Modelfile").
+				Lines(16).
+				CharLimit(0).
+				Value(&modelfile),
+			huh.NewConfirm().
+				Title("Would you like to continue?").
+				Value(&confirm),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	err = form.Run()
+	if err != nil {
+		return "", "", err
+	}
+
+	if !confirm {
+		return "", "", errors.New("see you")
+	}
+
+	return modelName, modelfile, nil
+}
+
+// Create builds modelName from the given Modelfile contents, showing the
+// progress like the TUI does. It is meant for non-interactive use, e.g.
+// `ollamanager create -f Modelfile my-model` in a build pipeline. Local GGUF
+// files and safetensors directories referenced by FROM or ADAPTER are
+// uploaded, relative paths are resolved against dir.
+func Create(modelName, modelfile, dir string, opts ...Option) error {
+	if err := validateModelName(modelName); err != nil {
+		return err
+	}
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	entry := ollamaAPI.beginHistory(string(tabs.CREATE), modelName)
+	err = ollamaAPI.createModel(modelName, modelfile, dir)
+	ollamaAPI.endHistory(entry, 0, err)
+
+	return err
+}
@@ -0,0 +1,228 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/embeddings"
+	"github.com/gaurav-gosain/ollamanager/tui"
+)
+
+// embedForm asks for the texts to embed, one per line.
+func embedForm(modelName string) ([]string, error) {
+	var text string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Texts to embed with " + modelName).
+				Description("One per line, the similarity of every pair is shown.").
+				Value(&text).
+				Validate(func(s string) error {
+					if len(splitLines(s)) == 0 {
+						return errors.New("enter at least one text")
+					}
+					return nil
+				}),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+
+	return splitLines(text), nil
+}
+
+// splitLines returns the non-blank lines of s.
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// embedTexts embeds the texts while showing a spinner, then prints the
+// report and offers to export the vectors.
+func (o OllamaAPI) embedTexts(modelName string, inputs []string) error {
+	var result embeddings.Result
+	var err error
+
+	spinnerErr := spinner.
+		New().
+		Title("Embedding " + fmt.Sprint(len(inputs)) + " texts with " + modelName + "...").
+		Action(func() {
+			result, err = embeddings.Embed(context.Background(), o.client, modelName, inputs)
+		}).
+		Run()
+	if spinnerErr != nil {
+		return spinnerErr
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to embed texts: %s", err.Error())
+		o.emitError(modelName, err)
+		return err
+	}
+
+	printEmbeddings(result)
+
+	return exportEmbeddingsForm(result)
+}
+
+// printEmbeddings prints the dimensions and norm of every vector and the
+// cosine similarity matrix.
+func printEmbeddings(result embeddings.Result) {
+	var report strings.Builder
+
+	fmt.Fprintf(&report, "%s %d dimensions\n\n", tui.StatusStyle.Render(result.Model), result.Dimensions())
+
+	w := tabwriter.NewWriter(&report, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tnorm\ttext")
+	for i, input := range result.Inputs {
+		fmt.Fprintf(w, "%d\t%.4f\t%s\n", i+1, embeddings.Norm(result.Vectors[i]), truncate(input, 60))
+	}
+	w.Flush()
+
+	if len(result.Inputs) > 1 {
+		fmt.Fprintf(&report, "\nCosine similarity\n\n")
+
+		w = tabwriter.NewWriter(&report, 0, 0, 2, ' ', tabwriter.AlignRight)
+		for i := range result.Inputs {
+			fmt.Fprintf(w, "\t%d", i+1)
+		}
+		fmt.Fprintln(w, "\t")
+		for i, row := range result.Similarity() {
+			fmt.Fprintf(w, "%d", i+1)
+			for _, similarity := range row {
+				fmt.Fprintf(w, "\t%.3f", similarity)
+			}
+			fmt.Fprintln(w, "\t")
+		}
+		w.Flush()
+	}
+
+	fmt.Println(lipgloss.NewStyle().Padding(0, 2).Render(report.String()))
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+// exportEmbeddingsForm asks whether to save the vectors as JSON or CSV.
+func exportEmbeddingsForm(result embeddings.Result) error {
+	format := "none"
+	var path string
+
+	// not in the alt screen, so the report printed above stays visible
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Export the vectors?").
+				Options(
+					huh.NewOption("No", "none"),
+					huh.NewOption("As JSON", "json"),
+					huh.NewOption("As CSV", "csv"),
+				).
+				Value(&format),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("File").
+				PlaceholderFunc(func() string { return "embeddings." + format }, &format).
+				Value(&path),
+		).WithHideFunc(func() bool { return format == "none" }),
+	)
+
+	if err := form.Run(); err != nil {
+		return err
+	}
+
+	if format == "none" {
+		return nil
+	}
+
+	if path = strings.TrimSpace(path); path == "" {
+		path = "embeddings." + format
+	}
+
+	return writeEmbeddings(result, path, format)
+}
+
+// writeEmbeddings writes the vectors to path as JSON or CSV.
+func writeEmbeddings(result embeddings.Result, path, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to export embeddings: %s", err.Error())
+	}
+	defer f.Close()
+
+	if format == "csv" {
+		err = result.WriteCSV(f)
+	} else {
+		err = result.WriteJSON(f)
+	}
+	if err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export embeddings: %s", err.Error())
+	}
+
+	fmt.Println(
+		lipgloss.NewStyle().Padding(0, 2).Render(
+			fmt.Sprintln("Embeddings exported to", tui.StatusStyle.Render(path)),
+		),
+	)
+
+	return nil
+}
+
+// Embed embeds the inputs with the model and prints the report. The vectors
+// are written to output when set, as CSV for a .csv file and JSON otherwise.
+func Embed(name string, inputs []string, output string, opts ...Option) error {
+	if err := validateModelName(name); err != nil {
+		return err
+	}
+	name = parseModelName(name).String()
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	result, err := embeddings.Embed(context.Background(), ollamaAPI.client, name, inputs)
+	if err != nil {
+		err = fmt.Errorf("failed to embed texts: %s", err.Error())
+		ollamaAPI.emitError(name, err)
+		return err
+	}
+
+	printEmbeddings(result)
+
+	if output == "" {
+		return nil
+	}
+
+	format := "json"
+	if strings.EqualFold(filepath.Ext(output), ".csv") {
+		format = "csv"
+	}
+
+	return writeEmbeddings(result, output, format)
+}
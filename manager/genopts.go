@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/genopts"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/tui"
+)
+
+// generationOptionsForm asks for the generation options of a request: the
+// model defaults, a saved preset, or either of them edited. Edited options can
+// be saved as a preset.
+func generationOptionsForm(title string) (genopts.Options, error) {
+	presets, err := genopts.Presets()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []huh.Option[string]{huh.NewOption("Model defaults", "")}
+	for _, name := range slices.Sorted(maps.Keys(presets)) {
+		options = append(options, huh.NewOption(
+			fmt.Sprintf("%s (%s)", name, presets[name]),
+			name,
+		))
+	}
+
+	var preset string
+	edit := false
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(title).
+				Description("Presets are saved in the option_presets of the config.").
+				Options(options...).
+				Value(&preset),
+			huh.NewConfirm().
+				Title("Edit the options?").
+				Value(&edit),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+
+	selected := genopts.Options{}
+	for name, value := range presets[preset] {
+		selected[name] = value
+	}
+
+	if !edit {
+		return selected, nil
+	}
+
+	return editOptionsForm(selected)
+}
+
+// editOptionsForm shows an input for every option, pre-filled from options,
+// saving the result as a preset when a name is given. The name starts empty,
+// so editing a preset does not overwrite it unless asked to.
+func editOptionsForm(options genopts.Options) (genopts.Options, error) {
+	var preset string
+	values := make([]string, len(genopts.Fields))
+	var fields []huh.Field
+
+	for i, f := range genopts.Fields {
+		values[i] = genopts.Format(options[f.Name])
+		fields = append(fields, huh.NewInput().
+			Title(f.Name).
+			Description(f.Description).
+			Placeholder("model default").
+			Value(&values[i]).
+			Validate(func(s string) error {
+				_, err := genopts.Parse(f.Name, s)
+				return err
+			}),
+		)
+	}
+
+	fields = append(fields, huh.NewInput().
+		Title("Save as preset").
+		Description("Leave empty to use the options only this time.").
+		Value(&preset),
+	)
+
+	form := huh.NewForm(
+		huh.NewGroup(fields...),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+
+	edited := genopts.Options{}
+	for i, f := range genopts.Fields {
+		if err := edited.Set(f.Name, values[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if preset = strings.TrimSpace(preset); preset != "" {
+		if err := genopts.SavePreset(preset, edited); err != nil {
+			return nil, fmt.Errorf("failed to save preset: %s", err.Error())
+		}
+		fmt.Println(
+			lipgloss.NewStyle().Padding(0, 2).Render(
+				fmt.Sprintln("Options saved as preset", tui.StatusStyle.Render(preset)),
+			),
+		)
+	}
+
+	return edited, nil
+}
+
+// Load keeps the model loaded indefinitely with the generation options, see
+// loadModel.
+func Load(name string, options genopts.Options, opts ...Option) error {
+	if err := validateModelName(name); err != nil {
+		return err
+	}
+	name = parseModelName(name).String()
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	entry := ollamaAPI.beginHistory(string(tabs.LOAD), name)
+	err = ollamaAPI.loadModel(name, options)
+	ollamaAPI.endHistory(entry, 0, err)
+
+	return err
+}
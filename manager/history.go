@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gaurav-gosain/ollamanager/history"
+	"github.com/gaurav-gosain/ollamanager/tui"
+)
+
+// digest returns the digest of an installed model, or an empty string if it
+// is not installed.
+func (o OllamaAPI) digest(modelName string) string {
+	list, err := o.client.List(context.Background())
+	if err != nil {
+		return ""
+	}
+
+	if !strings.Contains(modelName, ":") {
+		modelName += ":latest"
+	}
+
+	for _, model := range list.Models {
+		if model.Name == modelName {
+			return model.Digest
+		}
+	}
+
+	return ""
+}
+
+// beginHistory starts a history entry for the action. It returns nil when
+// history recording is disabled.
+func (o OllamaAPI) beginHistory(action, modelName string) *history.Entry {
+	if o.historyPath == "" {
+		return nil
+	}
+
+	entry := history.NewEntry(action, modelName)
+	entry.DigestBefore = o.digest(modelName)
+
+	return &entry
+}
+
+// endHistory completes the entry with the action outcome and appends it to
+// the history log. Failing to write the log never fails the action.
+func (o OllamaAPI) endHistory(entry *history.Entry, bytes int64, err error) {
+	if entry == nil {
+		return
+	}
+
+	entry.Finish(err)
+	if errors.Is(err, tui.ErrCancelled) {
+		entry.Outcome = history.CANCELLED
+	}
+	entry.Bytes = bytes
+	entry.DigestAfter = o.digest(entry.Model)
+
+	_ = history.Append(o.historyPath, *entry)
+}
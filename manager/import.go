@@ -0,0 +1,274 @@
+package manager
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/gaurav-gosain/ollamanager/store"
+	"github.com/ollama/ollama/api"
+)
+
+// resolveModelfile uploads the local files referenced by FROM and ADAPTER
+// instructions and points them at the uploaded blobs, like `ollama create`
+// does. Relative paths are resolved against dir.
+func (o OllamaAPI) resolveModelfile(modelfile, dir string, fn func(api.ProgressResponse) error) (string, error) {
+	lines := strings.Split(modelfile, "\n")
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		end := strings.IndexFunc(line, unicode.IsSpace)
+		if end < 0 {
+			continue
+		}
+
+		instruction, arg := strings.ToUpper(line[:end]), line[end:]
+		if instruction != "FROM" && instruction != "ADAPTER" {
+			continue
+		}
+
+		path := expandPath(unquotePath(arg), dir)
+		if _, err := os.Stat(path); err != nil {
+			// not a local file, e.g. FROM llama3.2
+			continue
+		}
+
+		digest, err := o.uploadBlob(path, fn)
+		if err != nil {
+			return "", err
+		}
+
+		lines[i] = fmt.Sprintf("%s @%s", instruction, digest)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// unquotePath returns the path argument of an instruction, which is either
+// quoted or taken as is, so unquoted paths may contain spaces too.
+func unquotePath(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+		if unquoted, err := strconv.Unquote(arg); err == nil {
+			return unquoted
+		}
+		return arg[1 : len(arg)-1]
+	}
+
+	return arg
+}
+
+func expandPath(path, dir string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	if !filepath.IsAbs(path) && dir != "" {
+		path = filepath.Join(dir, path)
+	}
+
+	return path
+}
+
+// uploadBlob hashes the file (or the zipped safetensors directory) at path
+// and uploads it to the server unless a blob with that digest already
+// exists. It returns the blob digest.
+func (o OllamaAPI) uploadBlob(path string, fn func(api.ProgressResponse) error) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(path)
+
+	if info.IsDir() {
+		zipPath, err := zipSafetensors(path)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(zipPath)
+
+		path = zipPath
+		if info, err = os.Stat(path); err != nil {
+			return "", err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, store.NewProgressReader(f, "hashing "+name, "", info.Size(), fn))
+	if err != nil {
+		return "", err
+	}
+	digest := fmt.Sprintf("sha256:%x", h.Sum(nil))
+
+	if o.hasBlob(digest) {
+		_ = fn(api.ProgressResponse{Status: "using existing blob " + digest})
+		return digest, nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	err = o.client.CreateBlob(context.Background(), digest, store.NewProgressReader(f, "uploading "+digest[7:19], digest, info.Size(), fn))
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s: %s", name, err.Error())
+	}
+
+	return digest, nil
+}
+
+// hasBlob checks whether the server already has the blob. The API client has
+// no method for it, so the blob endpoint is queried directly.
+func (o OllamaAPI) hasBlob(digest string) bool {
+	req, err := http.NewRequest(http.MethodHead, o.base.JoinPath("api", "blobs", digest).String(), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := o.http.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
+// zipSafetensors bundles the weights, config and tokenizer files of a
+// safetensors model directory into a temporary zip, the format the server
+// expects for safetensors imports.
+func zipSafetensors(dir string) (string, error) {
+	var files []string
+	for _, pattern := range []string{"*.safetensors", "*.json", "*.model", "*.tiktoken"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return "", err
+		}
+		files = append(files, matches...)
+	}
+
+	if len(files) == 0 {
+		return "", fmt.Errorf("no safetensors files found in %s", dir)
+	}
+
+	tmp, err := os.CreateTemp("", "ollamanager-*.zip")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+
+	zw := zip.NewWriter(tmp)
+	for _, file := range files {
+		if err := addToZip(zw, file); err != nil {
+			os.Remove(tmp.Name())
+			return "", err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	return tmp.Name(), nil
+}
+
+func addToZip(zw *zip.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := zw.Create(filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// importForm asks for a local GGUF file or safetensors directory and the
+// name, template and parameters of the model to create from it. It returns
+// the model name and a Modelfile referencing the local path.
+func importForm() (modelName, modelfile string, err error) {
+	var path, template, parameters string
+	confirm := false
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Path to a GGUF file or safetensors directory").
+				Placeholder("~/models/my-finetune.gguf").
+				Validate(func(s string) error {
+					if _, err := os.Stat(expandPath(s, "")); err != nil {
+						return errors.New("file not found")
+					}
+					return nil
+				}).
+				Value(&path),
+			huh.NewInput().
+				Title("Name of the new model").
+				Validate(validateModelName).
+				Value(&modelName),
+		),
+		huh.NewGroup(
+			huh.NewText().
+				Title("Template (optional)").
+				Placeholder("{{ .System }}\nUSER: {{ .Prompt }}\nASSISTANT: ").
+				CharLimit(0).
+				Value(&template),
+			huh.NewText().
+				Title("Parameters (optional, one per line)").
+				Placeholder("temperature 0.7\nnum_ctx 4096").
+				CharLimit(0).
+				Value(&parameters),
+			huh.NewConfirm().
+				Title("Would you like to continue?").
+				Value(&confirm),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	err = form.Run()
+	if err != nil {
+		return "", "", err
+	}
+
+	if !confirm {
+		return "", "", errors.New("see you")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "FROM %s\n", strconv.Quote(expandPath(path, "")))
+	for _, line := range strings.Split(parameters, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			fmt.Fprintf(&b, "PARAMETER %s\n", line)
+		}
+	}
+	if strings.TrimSpace(template) != "" {
+		fmt.Fprintf(&b, "TEMPLATE \"\"\"%s\"\"\"\n", template)
+	}
+
+	return modelName, b.String(), nil
+}
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+)
+
+func TestResolveModelfilePaths(t *testing.T) {
+	o, srv, _ := newTestAPI(t)
+
+	dir := filepath.Join(t.TempDir(), "my models")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	weights := []byte("GGUF weights")
+	if err := os.WriteFile(filepath.Join(dir, "fine tune.gguf"), weights, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(weights))
+
+	tests := []struct {
+		name, line, want string
+	}{
+		{"quoted", `FROM "fine tune.gguf"`, "FROM @" + digest},
+		{"spaces", "FROM fine tune.gguf", "FROM @" + digest},
+		{"tab", "adapter\tfine tune.gguf", "ADAPTER @" + digest},
+		{"absolute", `FROM "` + filepath.Join(dir, "fine tune.gguf") + `"`, "FROM @" + digest},
+		{"library", "FROM llama3.2", "FROM llama3.2"},
+		{"missing", `FROM "other.gguf"`, `FROM "other.gguf"`},
+		{"bare", "FROM", "FROM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := o.resolveModelfile(tt.line, dir, func(api.ProgressResponse) error { return nil })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("resolved = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if size, ok := srv.Blobs()[digest]; !ok || size != int64(len(weights)) {
+		t.Fatalf("blobs = %v", srv.Blobs())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/v2/progress"
@@ -14,27 +15,54 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/benchmark"
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/gaurav-gosain/ollamanager/events"
+	"github.com/gaurav-gosain/ollamanager/genopts"
+	"github.com/gaurav-gosain/ollamanager/policy"
 	"github.com/gaurav-gosain/ollamanager/tabs"
 	"github.com/gaurav-gosain/ollamanager/tui"
 	"github.com/gaurav-gosain/ollamanager/utils"
 	"github.com/ollama/ollama/api"
+	"github.com/ollama/ollama/envconfig"
 )
 
 type OllamaAPI struct {
 	client *api.Client
+	// base and http are what client was built from, for endpoints the API
+	// client has no method for.
+	base        *url.URL
+	http        *http.Client
+	bus         *events.Bus
+	historyPath string
 }
 
 func NewOllamaAPI() (OllamaAPI, error) {
-	client, err := api.ClientFromEnvironment()
-	if err != nil {
-		return OllamaAPI{}, err
-	}
+	base := envconfig.Host()
+	httpClient := http.DefaultClient
 
 	return OllamaAPI{
-		client: client,
+		client: api.NewClient(base, httpClient),
+		base:   base,
+		http:   httpClient,
 	}, nil
 }
 
+func (o OllamaAPI) emit(eventType events.Type, modelName string) {
+	o.bus.Emit(events.Event{
+		Type:  eventType,
+		Model: modelName,
+	})
+}
+
+func (o OllamaAPI) emitError(modelName string, err error) {
+	o.bus.Emit(events.Event{
+		Type:  events.ERROR,
+		Model: modelName,
+		Err:   err,
+	})
+}
+
 func (o OllamaAPI) installModel(modelName string, p *tea.Program) {
 	ctx := context.Background()
 
@@ -42,15 +70,23 @@ func (o OllamaAPI) installModel(modelName string, p *tea.Program) {
 		Model: modelName,
 	}
 	progressFunc := func(resp api.ProgressResponse) error {
+		o.bus.Emit(events.Event{
+			Type:     events.PULL_PROGRESS,
+			Model:    modelName,
+			Progress: &resp,
+		})
 		p.Send(resp)
 		return nil
 	}
 
 	err := o.client.Pull(ctx, req, progressFunc)
 	if err != nil {
-		fmt.Println("Error pulling model:", err.Error())
+		o.emitError(modelName, err)
+		p.Send(tui.ProgressError(fmt.Errorf("error pulling model: %s", err.Error())))
 		return
 	}
+
+	o.emit(events.PULL_DONE, modelName)
 }
 
 // deleteModel deletes a model by name It returns an error if the model is not
@@ -64,37 +100,47 @@ func (o OllamaAPI) deleteModel(modelName string) error {
 
 	err := o.client.Delete(ctx, req)
 	if err != nil {
-		return fmt.Errorf("failed to delete model: %s", err.Error())
+		err = fmt.Errorf("failed to delete model: %s", err.Error())
+		o.emitError(modelName, err)
+		return err
 	}
 
+	o.emit(events.DELETE, modelName)
+
 	return nil
 }
 
-func (o OllamaAPI) loadModel(modelName string) error {
+func (o OllamaAPI) loadModel(modelName string, options genopts.Options) error {
 	ctx := context.Background()
 
+	// options such as num_ctx are fixed when the model is loaded
 	req := &api.GenerateRequest{
 		Model: modelName,
 		KeepAlive: &api.Duration{
 			Duration: -1,
 		},
+		Options: options,
 	}
 
 	err := o.client.Generate(ctx, req, func(g api.GenerateResponse) error { return nil })
 	if err != nil {
-		return fmt.Errorf("failed to load model: %s", err.Error())
+		err = fmt.Errorf("failed to load model: %s", err.Error())
+		o.emitError(modelName, err)
+		return err
 	}
 
-	fmt.Println(
-		lipgloss.NewStyle().Padding(0, 2).Render(
-			fmt.Sprintln(
-				"Model",
-				tui.StatusStyle.Render(modelName),
-				"will stay loaded in memory",
-				tui.StatusStyle.Render("indefinitely"),
-			),
-		),
+	o.emit(events.LOAD, modelName)
+
+	loaded := fmt.Sprint(
+		"Model ",
+		tui.StatusStyle.Render(modelName),
+		" will stay loaded in memory ",
+		tui.StatusStyle.Render("indefinitely"),
 	)
+	if len(options) > 0 {
+		loaded += " with " + options.String()
+	}
+	fmt.Println(lipgloss.NewStyle().Padding(0, 2).Render(fmt.Sprintln(loaded)))
 
 	return nil
 }
@@ -111,9 +157,13 @@ func (o OllamaAPI) freeModel(modelName string) error {
 
 	err := o.client.Generate(ctx, req, func(g api.GenerateResponse) error { return nil })
 	if err != nil {
-		return fmt.Errorf("failed to free model: %s", err.Error())
+		err = fmt.Errorf("failed to free model: %s", err.Error())
+		o.emitError(modelName, err)
+		return err
 	}
 
+	o.emit(events.UNLOAD, modelName)
+
 	fmt.Println(
 		lipgloss.NewStyle().Padding(0, 2).Render(
 			fmt.Sprintln(
@@ -129,7 +179,7 @@ func (o OllamaAPI) freeModel(modelName string) error {
 }
 
 func GetAvailableTags(modelName string) ([]string, error) {
-	resp, err := http.Get("https://ollama.com/library/" + modelName + "/tags")
+	resp, err := http.Get(tui.LibraryURL + "/" + modelName + "/tags")
 	if err != nil {
 		return nil, err
 	}
@@ -157,24 +207,37 @@ func GetAvailableTags(modelName string) ([]string, error) {
 func Run(
 	selectedTabs []tabs.Tab,
 	approvedActions []tabs.ManageAction,
+	opts ...Option,
 ) (
 	result utils.OllamanagerResult,
 	err error,
 ) {
 	ctx := context.Background()
 
+	runOpts := newRunOptions(opts)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	purgeExpiredTrash(cfg)
+
 	var modelName string
 
 	modelSelector, err := tui.ModelPicker(
 		selectedTabs,
 		approvedActions,
+		tui.WithTimeline(runOpts.timeline),
 	)
 
 	// TODO: could be cleaner
 	if err != nil ||
-		(modelSelector.SelectedInstallableModel.Name == "" &&
+		(modelSelector.ManageAction != tabs.IMPORT &&
+			modelSelector.SelectedInstallableModel.Name == "" &&
 			modelSelector.SelectedInstalledModel.Name == "" &&
-			modelSelector.SelectedRunningModel.Name == "") {
+			modelSelector.SelectedRunningModel.Name == "" &&
+			modelSelector.SelectedTrashEntry.ID == "" &&
+			modelSelector.SelectedSession.ID == "") {
 		utils.ClearTerminal()
 		err = errors.New(`failed to pick a model :(`)
 		return
@@ -217,12 +280,27 @@ func Run(
 			return
 		}
 
+		var pol policy.Policy
+		pol, err = policy.Load()
+		if err != nil {
+			return
+		}
+
 		options := []huh.Option[string]{}
 
 		for _, modelTag := range modelTags {
+			// only offer the tags the allow and deny lists permit
+			if pol.CheckName(modelName+":"+modelTag) != nil {
+				continue
+			}
 			options = append(options, huh.NewOption(modelTag, modelTag))
 		}
 
+		if len(options) == 0 {
+			err = errors.New("no tag of " + modelName + " is allowed by the policy")
+			return
+		}
+
 		var tag string
 
 		form := huh.NewForm(
@@ -260,6 +338,10 @@ func Run(
 		modelName = modelSelector.SelectedInstalledModel.Name
 	case tabs.MONITOR:
 		modelName = modelSelector.SelectedRunningModel.Name
+	case tabs.TRASH:
+		modelName = modelSelector.SelectedTrashEntry.Model
+	case tabs.SESSIONS:
+		modelName = modelSelector.SelectedSession.Model
 	}
 
 	utils.ClearTerminal()
@@ -288,7 +370,7 @@ func Run(
 	var actionErr error
 	var res tea.Model
 
-	ollamaAPI, err := NewOllamaAPI()
+	ollamaAPI, err := newOllamaAPI(runOpts)
 	if err != nil {
 		fmt.Println("Error creating client:", err)
 		return
@@ -296,6 +378,15 @@ func Run(
 
 	switch modelSelector.Action {
 	case tabs.INSTALL:
+		if err = ollamaAPI.enforcePolicy(modelName); err != nil {
+			return
+		}
+		if err = ollamaAPI.ensureDiskBudget(modelName); err != nil {
+			return
+		}
+
+		entry := ollamaAPI.beginHistory(string(tabs.INSTALL), modelName)
+
 		go ollamaAPI.installModel(modelName, p)
 		res, err = p.Run()
 		if err != nil {
@@ -304,9 +395,19 @@ func Run(
 		}
 
 		actionErr = res.(tui.InstallModel).Err
+		ollamaAPI.endHistory(entry, res.(tui.InstallModel).Transferred(), actionErr)
 	case tabs.MANAGE:
 		switch modelSelector.ManageAction {
 		case tabs.UPDATE:
+			if err = ollamaAPI.enforcePolicy(modelName); err != nil {
+				return
+			}
+			if err = ollamaAPI.ensureDiskBudget(modelName); err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory(string(tabs.UPDATE), modelName)
+
 			go ollamaAPI.installModel(modelName, p)
 
 			res, err = p.Run()
@@ -316,12 +417,165 @@ func Run(
 			}
 
 			actionErr = res.(tui.InstallModel).Err
+			ollamaAPI.endHistory(entry, res.(tui.InstallModel).Transferred(), actionErr)
 		case tabs.DELETE:
 			modelName = modelSelector.SelectedInstalledModel.Name
-			actionErr = ollamaAPI.deleteModel(modelName)
+			if err = forcePinned(modelName, "delete"); err != nil {
+				return
+			}
+			if err = confirmDelete(modelSelector.SelectedInstalledModel, cfg); err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory(string(tabs.DELETE), modelName)
+			if cfg.TrashDays > 0 {
+				actionErr = ollamaAPI.trashModel(modelName)
+			} else {
+				actionErr = ollamaAPI.deleteModel(modelName)
+			}
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		case tabs.PIN:
+			actionErr = togglePin(modelName)
+		case tabs.CREATE:
+			var modelfile string
+			baseModel := modelName
+			modelName, modelfile, err = ollamaAPI.editModelfile(baseModel)
+			if err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory(string(tabs.CREATE), modelName)
+			actionErr = ollamaAPI.createModel(modelName, modelfile, "")
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		case tabs.IMPORT:
+			var modelfile string
+			modelName, modelfile, err = importForm()
+			if err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory(string(tabs.IMPORT), modelName)
+			actionErr = ollamaAPI.createModel(modelName, modelfile, "")
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		case tabs.COPY:
+			source := modelName
+			modelName, err = ollamaAPI.copyForm(source)
+			if err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory(string(tabs.COPY), modelName)
+			if entry != nil {
+				entry.Source = source
+			}
+			actionErr = ollamaAPI.copyModel(source, modelName)
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		case tabs.PUSH:
+			var target string
+			var insecure bool
+			target, insecure, err = pushForm(modelName)
+			if err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory(string(tabs.PUSH), target)
+			if entry != nil {
+				entry.Source = modelName
+			}
+			var transferred int64
+			transferred, actionErr = ollamaAPI.retagAndPush(modelName, target, insecure)
+			ollamaAPI.endHistory(entry, transferred, actionErr)
+			modelName = target
+		case tabs.BENCHMARK:
+			var benchOpts benchmark.Options
+			benchOpts, err = benchmarkForm(modelName)
+			if err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory(string(tabs.BENCHMARK), modelName)
+			actionErr = ollamaAPI.benchmarkModel(modelName, benchOpts)
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		case tabs.COMPARE:
+			var models []string
+			var prompt string
+			var options genopts.Options
+			models, prompt, options, err = ollamaAPI.compareForm(modelName)
+			if err != nil {
+				return
+			}
+
+			actionErr = ollamaAPI.compareModels(models, prompt, options)
+		case tabs.TOOLS:
+			var tools api.Tools
+			var prompt string
+			var options genopts.Options
+			tools, prompt, options, err = toolsForm(modelName)
+			if err != nil {
+				return
+			}
+
+			actionErr = ollamaAPI.toolChat(modelName, tools, prompt, options)
+		case tabs.SCHEMA:
+			var schema, prompt string
+			var runs int
+			var options genopts.Options
+			schema, prompt, runs, options, err = structuredForm(modelName)
+			if err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory(string(tabs.SCHEMA), modelName)
+			_, actionErr = ollamaAPI.validateStructured(modelName, schema, prompt, runs, options)
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		case tabs.TEMPLATE:
+			actionErr = ollamaAPI.previewTemplate(modelName)
+		case tabs.LOAD:
+			var options genopts.Options
+			options, err = generationOptionsForm("Options to load " + modelName + " with")
+			if err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory(string(tabs.LOAD), modelName)
+			actionErr = ollamaAPI.loadModel(modelName, options)
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		case tabs.EMBED:
+			var inputs []string
+			inputs, err = embedForm(modelName)
+			if err != nil {
+				return
+			}
+
+			actionErr = ollamaAPI.embedTexts(modelName, inputs)
 		case tabs.CHAT:
 			result.IsMultiModal = len(modelSelector.SelectedInstalledModel.Details.Families) > 1
 		}
+	case tabs.TRASH:
+		var trashAction string
+		trashAction, err = trashForm(modelSelector.SelectedTrashEntry)
+		if err != nil {
+			return
+		}
+
+		switch trashAction {
+		case "restore":
+			entry := ollamaAPI.beginHistory("Restore", modelName)
+			actionErr = restoreModel(modelSelector.SelectedTrashEntry.ID)
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		case "purge":
+			entry := ollamaAPI.beginHistory("Purge", modelName)
+			actionErr = purgeModel(modelSelector.SelectedTrashEntry.ID)
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		}
+	case tabs.SESSIONS:
+		var sessionAction, value string
+		sessionAction, value, err = sessionForm(modelSelector.SelectedSession)
+		if err != nil {
+			return
+		}
+
+		actionErr = ollamaAPI.manageSession(modelSelector.SelectedSession.ID, sessionAction, value)
 	case tabs.MONITOR:
 		// TODO: Implement running model
 		modelName = modelSelector.SelectedRunningModel.Name
@@ -341,6 +595,7 @@ func Run(
 					Title("Choose your tag for "+modelName).
 					Options(
 						huh.NewOption("Keep loaded in memory (indefinitely)", "load"),
+						huh.NewOption("Reload with other options (e.g. num_ctx)", "reload"),
 						huh.NewOption("Free up memory by unloading", "free"),
 						huh.NewOption("Do nothing", "none"),
 					).
@@ -362,9 +617,23 @@ func Run(
 
 		switch runningAction {
 		case "load":
-			actionErr = ollamaAPI.loadModel(modelName)
+			entry := ollamaAPI.beginHistory("Load", modelName)
+			actionErr = ollamaAPI.loadModel(modelName, nil)
+			ollamaAPI.endHistory(entry, 0, actionErr)
+		case "reload":
+			var options genopts.Options
+			options, err = generationOptionsForm("Options to load " + modelName + " with")
+			if err != nil {
+				return
+			}
+
+			entry := ollamaAPI.beginHistory("Load", modelName)
+			actionErr = ollamaAPI.loadModel(modelName, options)
+			ollamaAPI.endHistory(entry, 0, actionErr)
 		case "free":
+			entry := ollamaAPI.beginHistory("Unload", modelName)
 			actionErr = ollamaAPI.freeModel(modelName)
+			ollamaAPI.endHistory(entry, 0, actionErr)
 		case "none":
 			actionErr = nil
 		}
@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gaurav-gosain/ollamanager/events"
+	"github.com/gaurav-gosain/ollamanager/genopts"
+	"github.com/gaurav-gosain/ollamanager/history"
+	"github.com/gaurav-gosain/ollamanager/ollamatest"
+	"github.com/ollama/ollama/api"
+)
+
+// newTestAPI starts a fake Ollama server with llama3.2 installed and returns
+// a client for it recording its events. The config and data dirs are
+// temporary.
+func newTestAPI(t *testing.T) (OllamaAPI, *ollamatest.Server, *[]events.Event) {
+	t.Helper()
+
+	srv := ollamatest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddModel(ollamatest.Model("llama3.2", 2<<30))
+
+	t.Setenv("OLLAMA_HOST", srv.URL)
+	t.Setenv("OLLAMANAGER_CONFIG_DIR", t.TempDir())
+	t.Setenv("OLLAMANAGER_DATA_DIR", t.TempDir())
+
+	var emitted []events.Event
+	bus := events.NewBus()
+	bus.OnAny(func(e events.Event) { emitted = append(emitted, e) })
+
+	o, err := newOllamaAPI(newRunOptions([]Option{WithEvents(bus)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return o, srv, &emitted
+}
+
+func eventTypes(emitted []events.Event) []events.Type {
+	var types []events.Type
+	for _, e := range emitted {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestDeleteModel(t *testing.T) {
+	o, srv, emitted := newTestAPI(t)
+
+	if err := o.deleteModel("llama3.2:latest"); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.Installed()) != 0 {
+		t.Fatal("model still installed")
+	}
+
+	if err := o.deleteModel("llama3.2:latest"); err == nil {
+		t.Fatal("deleting a missing model succeeded")
+	}
+
+	if got, want := eventTypes(*emitted), []events.Type{events.DELETE, events.ERROR}; !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+func TestLoadAndFreeModel(t *testing.T) {
+	o, srv, emitted := newTestAPI(t)
+
+	if err := o.loadModel("llama3.2:latest", genopts.Options{"num_ctx": 8192}); err != nil {
+		t.Fatal(err)
+	}
+	if running := srv.Running(); len(running) != 1 {
+		t.Fatalf("running = %+v", running)
+	}
+
+	if err := o.freeModel("llama3.2:latest"); err != nil {
+		t.Fatal(err)
+	}
+	if running := srv.Running(); len(running) != 0 {
+		t.Fatalf("running = %+v", running)
+	}
+
+	if got, want := eventTypes(*emitted), []events.Type{events.LOAD, events.UNLOAD}; !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+func TestCreateRecordsHistory(t *testing.T) {
+	_, srv, _ := newTestAPI(t)
+	historyPath := t.TempDir() + "/history.jsonl"
+
+	err := Create("my-llama", "FROM llama3.2\nSYSTEM You are terse.\n", "", WithHistory(historyPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.ContainsFunc(srv.Installed(), func(m api.ListModelResponse) bool { return m.Name == "my-llama:latest" }) {
+		t.Fatalf("installed = %+v", srv.Installed())
+	}
+
+	entries, err := history.Read(historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Model != "my-llama" || entries[0].Outcome != history.SUCCESS || entries[0].DigestAfter == "" {
+		t.Fatalf("history = %+v", entries)
+	}
+}
@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	modelNamePart = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	modelHostPart = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*(:[0-9]+)?$`)
+)
+
+// modelName is a parsed `[host/][namespace/]model[:tag]` reference.
+type modelName struct {
+	Host      string
+	Namespace string
+	Model     string
+	Tag       string
+}
+
+// parseModelName splits a model reference into its parts, defaulting the tag
+// to "latest". It does not validate the parts.
+func parseModelName(name string) modelName {
+	var n modelName
+
+	parts := strings.Split(name, "/")
+	last := parts[len(parts)-1]
+
+	switch len(parts) {
+	case 2:
+		n.Namespace = parts[0]
+	case 3:
+		n.Host, n.Namespace = parts[0], parts[1]
+	}
+
+	n.Model, n.Tag, _ = strings.Cut(last, ":")
+	if n.Tag == "" {
+		n.Tag = "latest"
+	}
+
+	return n
+}
+
+func (n modelName) String() string {
+	var parts []string
+	if n.Host != "" {
+		parts = append(parts, n.Host)
+	}
+	if n.Namespace != "" {
+		parts = append(parts, n.Namespace)
+	}
+
+	return strings.Join(append(parts, n.Model), "/") + ":" + n.Tag
+}
+
+// validateModelName checks name against the `[host/][namespace/]model[:tag]`
+// syntax accepted by Ollama.
+func validateModelName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("model name cannot be empty")
+	}
+
+	if strings.Count(name, "/") > 2 {
+		return errors.New("model name must look like [host/][namespace/]model[:tag]")
+	}
+
+	n := parseModelName(name)
+
+	if n.Host != "" && !modelHostPart.MatchString(n.Host) {
+		return fmt.Errorf("invalid registry host %q", n.Host)
+	}
+
+	if strings.Count(name, "/") > 0 && !modelNamePart.MatchString(n.Namespace) {
+		return fmt.Errorf("invalid namespace %q", n.Namespace)
+	}
+
+	if !modelNamePart.MatchString(n.Model) {
+		return fmt.Errorf("invalid model %q, use letters, digits, '_', '-' and '.'", n.Model)
+	}
+
+	if !modelNamePart.MatchString(n.Tag) || len(n.Tag) > 80 {
+		return fmt.Errorf("invalid tag %q, use up to 80 letters, digits, '_', '-' and '.'", n.Tag)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"sync"
+
+	"github.com/gaurav-gosain/ollamanager/events"
+	"github.com/gaurav-gosain/ollamanager/timeline"
+)
+
+type runOptions struct {
+	bus         *events.Bus
+	hooks       []func(*events.Bus)
+	historyPath string
+	timeline    *timeline.Ring
+}
+
+// Option customizes a call to Run.
+type Option func(*runOptions)
+
+// WithEvents makes Run emit lifecycle events (pull progress, deletion,
+// loading, ...) on the given bus.
+func WithEvents(bus *events.Bus) Option {
+	return func(o *runOptions) {
+		o.bus = bus
+	}
+}
+
+// WithHooks registers typed callbacks for lifecycle events. It can be
+// combined with WithEvents, in which case the hooks are added to that bus
+// once, however many calls the option is passed to.
+func WithHooks(hooks events.Hooks) Option {
+	var mu sync.Mutex
+	registered := map[*events.Bus]bool{}
+
+	register := func(bus *events.Bus) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !registered[bus] {
+			registered[bus] = true
+			hooks.Register(bus)
+		}
+	}
+
+	return func(o *runOptions) {
+		o.hooks = append(o.hooks, register)
+	}
+}
+
+// WithHistory records every action performed by Run in the history log at
+// path (see history.Path for the default location).
+func WithHistory(path string) Option {
+	return func(o *runOptions) {
+		o.historyPath = path
+	}
+}
+
+// WithTimeline charts the samples of the ring on the Monitor tab. The ring
+// must be sampled by the caller, see timeline.Ring.Run.
+func WithTimeline(ring *timeline.Ring) Option {
+	return func(o *runOptions) {
+		o.timeline = ring
+	}
+}
+
+func newRunOptions(opts []Option) runOptions {
+	var o runOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if len(o.hooks) > 0 && o.bus == nil {
+		o.bus = events.NewBus()
+	}
+	for _, register := range o.hooks {
+		register(o.bus)
+	}
+
+	return o
+}
+
+// newOllamaAPI creates a client wired to the bus and history log of the
+// options.
+func newOllamaAPI(o runOptions) (OllamaAPI, error) {
+	ollamaAPI, err := NewOllamaAPI()
+	if err != nil {
+		return ollamaAPI, err
+	}
+
+	ollamaAPI.bus = o.bus
+	ollamaAPI.historyPath = o.historyPath
+
+	return ollamaAPI, nil
+}
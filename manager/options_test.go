@@ -0,0 +1,28 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/gaurav-gosain/ollamanager/events"
+)
+
+func TestWithHooksRegistersOnce(t *testing.T) {
+	bus := events.NewBus()
+
+	var deletes int
+	opts := []Option{
+		WithEvents(bus),
+		WithHooks(events.Hooks{OnDelete: func(string) { deletes++ }}),
+	}
+
+	// the main loop passes the same options to every Run
+	for range 3 {
+		newRunOptions(opts)
+	}
+
+	bus.Emit(events.Event{Type: events.DELETE, Model: "llama3.2:latest"})
+
+	if deletes != 1 {
+		t.Fatalf("hook called %d times, want 1", deletes)
+	}
+}
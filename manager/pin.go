@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/gaurav-gosain/ollamanager/tui"
+)
+
+// togglePin pins modelName in the config, or unpins it if it already is.
+func togglePin(modelName string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	pinned := !cfg.IsPinned(modelName)
+	cfg.SetPinned(modelName, pinned)
+
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %s", err.Error())
+	}
+
+	state := "unpinned"
+	if pinned {
+		state = "pinned " + tui.LOCK
+	}
+
+	fmt.Println(
+		lipgloss.NewStyle().Padding(0, 2).Render(
+			fmt.Sprintln("Model", tui.StatusStyle.Render(modelName), "is now", state),
+		),
+	)
+
+	return nil
+}
+
+// forcePinned returns nil when modelName is not pinned. Otherwise the action
+// (e.g. "delete") only goes ahead once the user types the model name, and is
+// refused outright when there is no terminal to ask on.
+// validateTypedName accepts modelName, or nothing to cancel.
+func validateTypedName(modelName string) func(string) error {
+	return func(s string) error {
+		if s != "" && s != modelName {
+			return errors.New("name does not match")
+		}
+		return nil
+	}
+}
+
+func forcePinned(modelName, action string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	if !cfg.IsPinned(modelName) {
+		return nil
+	}
+
+	refused := fmt.Errorf("%s is pinned, unpin it before you %s it", modelName, action)
+
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return refused
+	}
+
+	var typed string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("%s %s is pinned", tui.LOCK, modelName)).
+				Description(fmt.Sprintf("Type the model name to %s it anyway, leave empty to cancel.", action)).
+				Validate(validateTypedName(modelName)).
+				Value(&typed),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return err
+	}
+
+	if typed != modelName {
+		return refused
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package manager
+
+import (
+	"github.com/gaurav-gosain/ollamanager/policy"
+)
+
+// enforcePolicy refuses to pull modelName when the policy (see policy.Load)
+// does not allow it.
+func (o OllamaAPI) enforcePolicy(modelName string) error {
+	p, err := policy.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := p.Enforce(modelName); err != nil {
+		o.emitError(modelName, err)
+		return err
+	}
+
+	return nil
+}
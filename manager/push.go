@@ -0,0 +1,103 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/gaurav-gosain/ollamanager/events"
+	"github.com/ollama/ollama/api"
+)
+
+// pushModel pushes modelName to its registry, showing the per-layer upload
+// progress in the install view. It returns the number of bytes uploaded.
+func (o OllamaAPI) pushModel(modelName string, insecure bool) (int64, error) {
+	ctx := context.Background()
+
+	req := &api.PushRequest{
+		Model:    modelName,
+		Insecure: insecure,
+	}
+
+	transferred, err := runWithProgress(func(fn func(api.ProgressResponse) error) error {
+		return o.client.Push(ctx, req, fn)
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to push model: %s", err.Error())
+		o.emitError(modelName, err)
+		return transferred, err
+	}
+
+	o.emit(events.PUSH, modelName)
+
+	return transferred, nil
+}
+
+// pushForm asks for the registry reference to push source to and whether the
+// registry is insecure.
+func pushForm(source string) (target string, insecure bool, err error) {
+	n := parseModelName(source)
+	if n.Host != "" {
+		target = n.String()
+	}
+
+	confirm := false
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Push "+source+" to").
+				Description("The model is retagged to this name before pushing.").
+				Placeholder("registry.example.com/team/"+n.Model+":"+n.Tag).
+				Validate(validatePushTarget).
+				Value(&target),
+			huh.NewConfirm().
+				Title("Is this an insecure registry (plain HTTP or self-signed)?").
+				Value(&insecure),
+			huh.NewConfirm().
+				Title("Would you like to continue?").
+				Value(&confirm),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err = form.Run(); err != nil {
+		return "", false, err
+	}
+
+	if !confirm {
+		return "", false, errors.New("see you")
+	}
+
+	return parseModelName(target).String(), insecure, nil
+}
+
+// validatePushTarget requires the full registry/namespace/model:tag form, as
+// models without a registry host would be pushed to ollama.com.
+func validatePushTarget(target string) error {
+	if err := validateModelName(target); err != nil {
+		return err
+	}
+
+	n := parseModelName(target)
+	if n.Host == "" || n.Namespace == "" {
+		return errors.New("use the registry/namespace/model:tag form")
+	}
+
+	return nil
+}
+
+// retagAndPush copies source to target when they differ and pushes target.
+func (o OllamaAPI) retagAndPush(source, target string, insecure bool) (int64, error) {
+	if parseModelName(source).String() != target {
+		if err := forcePinned(target, "overwrite"); err != nil {
+			return 0, err
+		}
+		if err := o.copyModel(source, target); err != nil {
+			return 0, err
+		}
+	}
+
+	return o.pushModel(target, insecure)
+}
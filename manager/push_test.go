@@ -0,0 +1,40 @@
+package manager
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/gaurav-gosain/ollamanager/ollamatest"
+)
+
+func TestRetagRefusesPinnedTarget(t *testing.T) {
+	o, srv, _ := newTestAPI(t)
+	srv.AddModel(ollamatest.Model("team-model", 1<<30))
+
+	cfg := config.Config{}
+	cfg.SetPinned("team-model", true)
+	if err := config.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// without a terminal the pin guard refuses instead of asking
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	os.Stdin = r
+
+	if _, err := o.retagAndPush("llama3.2", "team-model:latest", false); err == nil {
+		t.Fatal("overwrote a pinned model")
+	}
+	for _, model := range srv.Installed() {
+		if model.Name == "team-model:latest" && model.Digest != ollamatest.Model("team-model", 1<<30).Digest {
+			t.Fatal("pinned model was retargeted")
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/session"
+	"github.com/gaurav-gosain/ollamanager/tui"
+)
+
+// sessionForm asks what to do with a saved chat session. The new name or the
+// export path is returned along with the action.
+func sessionForm(entry tui.SessionEntry) (action, value string, err error) {
+	name := entry.Name
+	confirm := false
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(entry.Name+" with "+entry.Model).
+				Options(
+					huh.NewOption("Resume it", "resume"),
+					huh.NewOption("Rename it", "rename"),
+					huh.NewOption("Export it as Markdown", "markdown"),
+					huh.NewOption("Export it as JSON", "json"),
+					huh.NewOption("Delete it", "delete"),
+					huh.NewOption("Do nothing", ""),
+				).
+				Value(&action),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("New name").
+				Value(&name).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("name cannot be empty")
+					}
+					return nil
+				}),
+		).WithHideFunc(func() bool { return action != "rename" }),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("File").
+				PlaceholderFunc(func() string { return exportPath(entry.Session, action) }, &action).
+				Value(&value),
+		).WithHideFunc(func() bool { return action != "markdown" && action != "json" }),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Would you like to continue?").
+				Value(&confirm),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err = form.Run(); err != nil {
+		return "", "", err
+	}
+
+	if !confirm {
+		return "", "", nil
+	}
+
+	switch action {
+	case "rename":
+		value = strings.TrimSpace(name)
+	case "markdown", "json":
+		if value = strings.TrimSpace(value); value == "" {
+			value = exportPath(entry.Session, action)
+		}
+	}
+
+	return action, value, nil
+}
+
+// exportPath is the default file a session is exported to, named after the
+// session ID.
+func exportPath(s session.Session, format string) string {
+	if format == "markdown" {
+		return s.ID + ".md"
+	}
+	return s.ID + ".json"
+}
+
+// exportSession writes the session to path as Markdown or JSON.
+func exportSession(s session.Session, path, format string) error {
+	var data []byte
+	var err error
+
+	if format == "markdown" {
+		data = []byte(s.Markdown())
+	} else {
+		data, err = s.JSON()
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export session: %s", err.Error())
+	}
+
+	fmt.Println(
+		lipgloss.NewStyle().Padding(0, 2).Render(
+			fmt.Sprintln("Session exported to", tui.StatusStyle.Render(path)),
+		),
+	)
+
+	return nil
+}
+
+// manageSession applies the action picked in sessionForm.
+func (o OllamaAPI) manageSession(id, action, value string) error {
+	s, err := session.Load(id)
+	if err != nil {
+		return fmt.Errorf("failed to load session: %s", err.Error())
+	}
+
+	switch action {
+	case "resume":
+		return o.chat(&s)
+	case "rename":
+		if err := session.Rename(id, value); err != nil {
+			return fmt.Errorf("failed to rename session: %s", err.Error())
+		}
+		fmt.Println(
+			lipgloss.NewStyle().Padding(0, 2).Render(
+				fmt.Sprintln("Session renamed to", tui.StatusStyle.Render(value)),
+			),
+		)
+	case "markdown", "json":
+		return exportSession(s, value, action)
+	case "delete":
+		if err := session.Delete(id); err != nil {
+			return fmt.Errorf("failed to delete session: %s", err.Error())
+		}
+		fmt.Println(
+			lipgloss.NewStyle().Padding(0, 2).Render(
+				fmt.Sprintln("Deleted session", tui.StatusStyle.Render(s.Name)),
+			),
+		)
+	}
+
+	return nil
+}
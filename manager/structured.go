@@ -0,0 +1,168 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/genopts"
+	"github.com/gaurav-gosain/ollamanager/structured"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/envconfig"
+)
+
+// structuredForm asks for the JSON schema, the prompt, how many times to send
+// it and the generation options. The schema is kept to be offered next time.
+func structuredForm(modelName string) (schema, prompt string, runs int, options genopts.Options, err error) {
+	schema, err = structured.LoadSchema()
+	if err != nil {
+		return "", "", 0, nil, err
+	}
+
+	runsText := "10"
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("JSON schema for "+modelName).
+				Description("Sent as the format of the response, which is validated against it.").
+				Lines(14).
+				Value(&schema).
+				Validate(func(s string) error {
+					_, err := structured.ParseSchema([]byte(s))
+					return err
+				}),
+			huh.NewText().
+				Title("Prompt").
+				Value(&prompt).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("prompt cannot be empty")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Runs").
+				Description("How many times to send the prompt.").
+				Value(&runsText).
+				Validate(func(s string) error {
+					n, err := strconv.Atoi(strings.TrimSpace(s))
+					if err != nil || n <= 0 {
+						return errors.New("enter a positive number")
+					}
+					return nil
+				}),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err = form.Run(); err != nil {
+		return "", "", 0, nil, err
+	}
+
+	if err = structured.SaveSchema(schema); err != nil {
+		return "", "", 0, nil, fmt.Errorf("failed to save schema: %s", err.Error())
+	}
+
+	options, err = generationOptionsForm("Options for the structured output of " + modelName)
+	if err != nil {
+		return "", "", 0, nil, err
+	}
+
+	runs, _ = strconv.Atoi(strings.TrimSpace(runsText))
+
+	return schema, strings.TrimSpace(prompt), runs, options, nil
+}
+
+// validateStructured sends the prompt with the schema runs times and prints
+// every attempt, the conformance rate and the validation problems.
+func (o OllamaAPI) validateStructured(modelName, schema, prompt string, runs int, options genopts.Options) (structured.Result, error) {
+	style := lipgloss.NewStyle().Padding(0, 2)
+
+	fmt.Println(style.Render(fmt.Sprintf(
+		"Validating the structured output of %s over %d runs",
+		tui.StatusStyle.Render(modelName),
+		runs,
+	)))
+
+	result, err := structured.Run(
+		context.Background(),
+		envconfig.Host(),
+		modelName,
+		prompt,
+		[]byte(schema),
+		options,
+		runs,
+		func(a structured.Attempt) {
+			status := "✓ valid"
+			if !a.Valid() {
+				status = fmt.Sprintf("✗ %d problems", len(a.Problems))
+			}
+			fmt.Println(style.Render(fmt.Sprintf(
+				"[%d/%d] %s • %s • %s",
+				a.Run,
+				runs,
+				status,
+				a.Duration.Round(time.Millisecond),
+				truncate(strings.Join(strings.Fields(a.Output), " "), 60),
+			)))
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf("failed to validate structured output: %s", err.Error())
+		o.emitError(modelName, err)
+		return result, err
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(
+		&report,
+		"\n%s conformance: %d/%d (%.0f%%)\n",
+		tui.StatusStyle.Render(modelName),
+		result.Valid(),
+		len(result.Attempts),
+		result.Rate()*100,
+	)
+	for _, problem := range result.Problems() {
+		fmt.Fprintf(&report, "\n%3d× %s", problem.Count, problem.Text)
+	}
+
+	fmt.Println(style.Render(report.String()))
+
+	return result, nil
+}
+
+// ValidateStructured sends the prompt with the schema runs times, see
+// validateStructured. It fails when less than minRate of the responses, from
+// 0 to 1, conform to the schema.
+func ValidateStructured(name string, schema []byte, prompt string, runs int, minRate float64, options genopts.Options, opts ...Option) error {
+	if err := validateModelName(name); err != nil {
+		return err
+	}
+	name = parseModelName(name).String()
+
+	if _, err := structured.ParseSchema(schema); err != nil {
+		return err
+	}
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	result, err := ollamaAPI.validateStructured(name, string(schema), prompt, runs, options)
+	if err != nil {
+		return err
+	}
+
+	if result.Rate() < minRate {
+		return fmt.Errorf("%.0f%% of the responses conform to the schema, %.0f%% required", result.Rate()*100, minRate*100)
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/chattemplate"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/api"
+)
+
+// defaultTemplate is what Ollama uses for models created without a template.
+const defaultTemplate = "{{ .Prompt }}"
+
+// showTemplate returns the Show response of modelName with its template,
+// defaultTemplate when it has none.
+func (o OllamaAPI) showTemplate(modelName string) (*api.ShowResponse, error) {
+	show, err := o.client.Show(context.Background(), &api.ShowRequest{Model: modelName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to show model: %s", err.Error())
+	}
+
+	if show.Template == "" {
+		show.Template = defaultTemplate
+	}
+
+	return show, nil
+}
+
+// previewTemplate renders the chat template of modelName with a sample
+// conversation and prints the raw prompt, then lets the user render another
+// sample, edit the template or save the edited template as a new model.
+func (o OllamaAPI) previewTemplate(modelName string) error {
+	show, err := o.showTemplate(modelName)
+	if err != nil {
+		o.emitError(modelName, err)
+		return err
+	}
+
+	samples := chattemplate.Samples(show.System)
+	sample := samples[0]
+	tmpl := show.Template
+
+	for {
+		printPrompt(tmpl, sample)
+
+		action, err := templateActionForm(samples, sample.Name, tmpl != show.Template)
+		if err != nil {
+			return err
+		}
+
+		switch action {
+		case "edit":
+			if tmpl, err = editTemplateForm(modelName, tmpl); err != nil {
+				return err
+			}
+		case "save":
+			var newModel string
+			newModel, err = newModelForm(modelName)
+			if err != nil {
+				return err
+			}
+
+			edited := *show
+			edited.Template = tmpl
+
+			entry := o.beginHistory(string(tabs.CREATE), newModel)
+			err = o.createModel(newModel, modelfileFromShow(modelName, &edited), "")
+			o.endHistory(entry, 0, err)
+
+			return err
+		case "":
+			return nil
+		default:
+			sample, _ = chattemplate.FindSample(action, show.System)
+		}
+	}
+}
+
+// printPrompt prints the raw prompt the template makes of the sample between
+// two rules, so leading and trailing whitespace can be told apart.
+func printPrompt(tmpl string, sample chattemplate.Sample) {
+	style := lipgloss.NewStyle().Padding(0, 2)
+
+	prompt, err := chattemplate.Render(tmpl, sample)
+	if err != nil {
+		fmt.Println(style.Render(err.Error()))
+		return
+	}
+
+	rule := lipgloss.NewStyle().Faint(true).Render(strings.Repeat("─", 40))
+
+	fmt.Println(style.Render(fmt.Sprintf(
+		"\nRaw prompt of the %s sample (%d characters, %d lines):",
+		tui.StatusStyle.Render(sample.Name),
+		len([]rune(prompt)),
+		strings.Count(prompt, "\n")+1,
+	)))
+	fmt.Println(rule)
+	fmt.Println(prompt)
+	fmt.Println(rule)
+}
+
+// templateActionForm asks what to do next with the template.
+func templateActionForm(samples []chattemplate.Sample, current string, edited bool) (string, error) {
+	var options []huh.Option[string]
+	for _, sample := range samples {
+		if sample.Name != current {
+			options = append(options, huh.NewOption("Render the "+sample.Name+" sample", sample.Name))
+		}
+	}
+	options = append(options, huh.NewOption("Edit the template", "edit"))
+	if edited {
+		options = append(options, huh.NewOption("Save it as a new model", "save"))
+	}
+	options = append(options, huh.NewOption("Done", ""))
+
+	var action string
+
+	// not in the alt screen, so the prompt printed above stays visible
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("What next?").
+				Options(options...).
+				Value(&action),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return "", err
+	}
+
+	return action, nil
+}
+
+// editTemplateForm lets the user edit the template, which must parse.
+func editTemplateForm(modelName, tmpl string) (string, error) {
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Template of " + modelName).
+				Description("A Go template, see the template docs of Ollama.").
+				Lines(20).
+				CharLimit(0).
+				Value(&tmpl).
+				Validate(chattemplate.Parse),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return "", err
+	}
+
+	return tmpl, nil
+}
+
+// newModelForm asks for the name of the model to save the edited template
+// as.
+func newModelForm(baseModel string) (string, error) {
+	var modelName string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Name of the new model").
+				Description("Created from " + baseModel + " with the edited template.").
+				Placeholder("my-" + strings.Split(baseModel, ":")[0]).
+				Validate(validateModelName).
+				Value(&modelName),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return "", err
+	}
+
+	return modelName, nil
+}
+
+// RenderTemplate returns the raw prompt the chat template of the model makes
+// of the named sample, see chattemplate.Samples. tmpl replaces the template
+// of the model when not empty, to try changes before creating a model.
+func RenderTemplate(name, sampleName, tmpl string, opts ...Option) (string, error) {
+	if err := validateModelName(name); err != nil {
+		return "", err
+	}
+	name = parseModelName(name).String()
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return "", err
+	}
+
+	show, err := ollamaAPI.showTemplate(name)
+	if err != nil {
+		return "", err
+	}
+
+	if tmpl == "" {
+		tmpl = show.Template
+	}
+
+	sample, err := chattemplate.FindSample(sampleName, show.System)
+	if err != nil {
+		return "", err
+	}
+
+	return chattemplate.Render(tmpl, sample)
+}
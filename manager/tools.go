@@ -0,0 +1,186 @@
+package manager
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/genopts"
+	"github.com/gaurav-gosain/ollamanager/toolcall"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/api"
+)
+
+// toolsForm asks for the tool definitions, the first message and the
+// generation options. The tools are kept to be offered next time.
+func toolsForm(modelName string) (tools api.Tools, prompt string, options genopts.Options, err error) {
+	definitions, err := toolcall.Load()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Tools for "+modelName).
+				Description("A JSON array of tools, as sent in the tools of a chat request.").
+				Lines(16).
+				Value(&definitions).
+				Validate(func(s string) error {
+					_, err := toolcall.Parse([]byte(s))
+					return err
+				}),
+			huh.NewText().
+				Title("First message").
+				Value(&prompt).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("message cannot be empty")
+					}
+					return nil
+				}),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err = form.Run(); err != nil {
+		return nil, "", nil, err
+	}
+
+	if err = toolcall.Save(definitions); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to save tools: %s", err.Error())
+	}
+
+	tools, err = toolcall.Parse([]byte(definitions))
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	options, err = generationOptionsForm("Options for the chat with " + modelName)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return tools, strings.TrimSpace(prompt), options, nil
+}
+
+// toolChat chats with the model and its tools in the terminal. Every tool
+// call is shown and checked against the tool definitions, and its result is
+// typed in before the model continues. It ends on /exit or at the end of the
+// input, with a summary of the calls.
+func (o OllamaAPI) toolChat(modelName string, tools api.Tools, prompt string, options genopts.Options) error {
+	style := lipgloss.NewStyle().Padding(0, 2)
+	you := tui.StatusStyle.Render("you")
+	model := tui.StatusStyle.Render(modelName)
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Function.Name)
+	}
+	fmt.Println(style.Render("Tool calling with " + model + ", tools: " + strings.Join(names, ", ")))
+	fmt.Println(style.Render("Type the result of every tool call, or nothing to send {}. Type /exit or press ctrl+d to leave."))
+	fmt.Printf("\n%s %s\n", you, prompt)
+
+	input := bufio.NewReader(os.Stdin)
+	messages := []api.Message{{Role: "user", Content: prompt}}
+	calls, invalid := 0, 0
+
+loop:
+	for {
+		fmt.Printf("\n%s ", model)
+		reply, err := o.toolReply(modelName, messages, tools, options)
+		fmt.Println()
+		if err != nil && !errors.Is(err, context.Canceled) {
+			o.emitError(modelName, err)
+			return fmt.Errorf("failed to chat: %s", err.Error())
+		}
+
+		messages = append(messages, reply)
+
+		if len(reply.ToolCalls) == 0 {
+			var line string
+			for line == "" {
+				fmt.Printf("\n%s ", you)
+				var ok bool
+				if line, ok = readLine(input); !ok {
+					break loop
+				}
+			}
+			messages = append(messages, api.Message{Role: "user", Content: line})
+			continue
+		}
+
+		for _, call := range reply.ToolCalls {
+			calls++
+
+			fmt.Println(style.Render(fmt.Sprintf("→ %s(%s)", call.Function.Name, call.Function.Arguments.String())))
+			if problems := toolcall.Check(tools, call); len(problems) > 0 {
+				invalid++
+				for _, problem := range problems {
+					fmt.Println(style.Render("  ✗ " + problem))
+				}
+			}
+
+			fmt.Printf("%s ", tui.StatusStyle.Render(call.Function.Name+" result"))
+			result, ok := readLine(input)
+			if !ok {
+				break loop
+			}
+			if result == "" {
+				result = "{}"
+			}
+			messages = append(messages, api.Message{Role: "tool", Content: result})
+		}
+	}
+
+	fmt.Println(style.Render(fmt.Sprintf(
+		"%d tool calls, %d with problems",
+		calls,
+		invalid,
+	)))
+
+	return nil
+}
+
+// toolReply streams the reply of the model to stdout. Interrupting stops the
+// reply.
+func (o OllamaAPI) toolReply(modelName string, messages []api.Message, tools api.Tools, options genopts.Options) (api.Message, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return toolcall.Chat(ctx, o.client, modelName, messages, tools, options, func(chunk string) {
+		fmt.Print(chunk)
+	})
+}
+
+// ToolChat chats with the model and the tools defined in the JSON file, see
+// toolChat.
+func ToolChat(name, toolsFile, prompt string, options genopts.Options, opts ...Option) error {
+	if err := validateModelName(name); err != nil {
+		return err
+	}
+	name = parseModelName(name).String()
+
+	data, err := os.ReadFile(toolsFile)
+	if err != nil {
+		return err
+	}
+
+	tools, err := toolcall.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	return ollamaAPI.toolChat(name, tools, prompt, options)
+}
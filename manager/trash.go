@@ -0,0 +1,252 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/gaurav-gosain/ollamanager/events"
+	"github.com/gaurav-gosain/ollamanager/store"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/envconfig"
+)
+
+// confirmDelete shows the size of the model and whether its blobs are shared
+// with other models, and asks for confirmation before deleting it. When the
+// model can't be moved to the trash, the user has to type its name.
+func confirmDelete(model tui.InstalledOllamaModel, cfg config.Config) error {
+	lines := []string{"Size " + humanize.Bytes(uint64(model.Size))}
+
+	s := store.Open("")
+	shared, sharedErr := s.SharedBlobs(model.Name)
+	manifest, manifestErr := s.ReadManifest(model.Name)
+	switch {
+	case errors.Join(sharedErr, manifestErr) != nil:
+		lines = append(lines, "Shared blobs could not be checked, the models directory is not readable")
+	case len(shared) == 0:
+		lines = append(lines, "No blobs are shared with other models")
+	default:
+		var sharedSize int64
+		for _, layer := range shared {
+			sharedSize += layer.Size
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%d of %d blobs (%s) are shared with other models and stay on disk",
+			len(shared),
+			len(manifest.Blobs()),
+			humanize.Bytes(uint64(sharedSize)),
+		))
+	}
+
+	permanent := true
+	if cfg.TrashDays > 0 {
+		if err := trashUnavailable(s); err != nil {
+			lines = append(lines, "The trash can't be used ("+err.Error()+"), this cannot be undone")
+		} else {
+			lines = append(lines, fmt.Sprintf("It is moved to the trash and purged after %d days", cfg.TrashDays))
+			permanent = false
+		}
+	} else {
+		lines = append(lines, "This cannot be undone")
+	}
+
+	confirm := false
+	var typed string
+
+	var field huh.Field
+	if permanent {
+		lines = append(lines, "", "Type the model name to delete it, leave empty to cancel.")
+		field = huh.NewInput().
+			Title("Delete " + model.Name + "?").
+			Description(strings.Join(lines, "\n")).
+			Validate(validateTypedName(model.Name)).
+			Value(&typed)
+	} else {
+		field = huh.NewConfirm().
+			Title("Delete " + model.Name + "?").
+			Description(strings.Join(lines, "\n")).
+			Affirmative("Delete").
+			Negative("Cancel").
+			Value(&confirm)
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(field),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return err
+	}
+
+	if !confirm && typed != model.Name {
+		return errors.New("see you")
+	}
+
+	return nil
+}
+
+// trashUnavailable returns why models can't be moved to the trash: the
+// Ollama server is remote, so the local models directory is not the one it
+// serves, or the directory is not writable.
+func trashUnavailable(s store.Store) error {
+	if host := envconfig.Host(); !isLocalHost(host.Hostname()) {
+		return fmt.Errorf("the Ollama server at %s is not local", host.Host)
+	}
+
+	return s.Writable()
+}
+
+// isLocalHost reports whether host is this machine.
+func isLocalHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+// trashModel soft-deletes modelName by moving it to the trash of the local
+// models directory. The model is unloaded first, as the server would keep
+// serving it from memory. When the trash can't be used the model is deleted
+// through the API instead.
+func (o OllamaAPI) trashModel(modelName string) error {
+	s := store.Open("")
+
+	if err := trashUnavailable(s); err != nil {
+		fmt.Println(
+			lipgloss.NewStyle().Padding(0, 2).Render(
+				fmt.Sprintln(
+					"The trash can't be used,",
+					err.Error()+",",
+					"deleting",
+					tui.StatusStyle.Render(modelName),
+					"permanently",
+				),
+			),
+		)
+		return o.deleteModel(modelName)
+	}
+
+	if err := o.unloadIfRunning(modelName); err != nil {
+		return err
+	}
+
+	item, err := s.Trash(modelName)
+	if err != nil {
+		err = fmt.Errorf("failed to move model to the trash: %s", err.Error())
+		o.emitError(modelName, err)
+		return err
+	}
+
+	o.emit(events.DELETE, modelName)
+
+	fmt.Println(
+		lipgloss.NewStyle().Padding(0, 2).Render(
+			fmt.Sprintln(
+				"Moved",
+				tui.StatusStyle.Render(modelName),
+				"to the trash,",
+				humanize.Bytes(uint64(item.Freed)),
+				"freed",
+			),
+		),
+	)
+
+	return nil
+}
+
+// unloadIfRunning unloads modelName when it is loaded.
+func (o OllamaAPI) unloadIfRunning(modelName string) error {
+	running, err := o.client.ListRunning(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list running models: %s", err.Error())
+	}
+
+	name := parseModelName(modelName).String()
+	for _, model := range running.Models {
+		if parseModelName(model.Name).String() == name {
+			return o.freeModel(model.Name)
+		}
+	}
+
+	return nil
+}
+
+// trashForm asks whether to restore or purge a trashed model. It returns an
+// empty action when the user picks neither.
+func trashForm(entry tui.TrashEntry) (action string, err error) {
+	confirm := false
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(entry.Model+" was deleted "+humanize.Time(entry.DeletedAt)).
+				Options(
+					huh.NewOption("Restore it", "restore"),
+					huh.NewOption("Purge it permanently", "purge"),
+					huh.NewOption("Do nothing", ""),
+				).
+				Value(&action),
+			huh.NewConfirm().
+				Title("Would you like to continue?").
+				Value(&confirm),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err = form.Run(); err != nil {
+		return "", err
+	}
+
+	if !confirm {
+		return "", nil
+	}
+
+	return action, nil
+}
+
+func restoreModel(id string) error {
+	item, err := store.Open("").Restore(id)
+	if err != nil {
+		return fmt.Errorf("failed to restore model: %s", err.Error())
+	}
+
+	fmt.Println(
+		lipgloss.NewStyle().Padding(0, 2).Render(
+			fmt.Sprintln("Restored", tui.StatusStyle.Render(item.Model)),
+		),
+	)
+
+	return nil
+}
+
+func purgeModel(id string) error {
+	item, err := store.Open("").Purge(id)
+	if err != nil {
+		return fmt.Errorf("failed to purge model: %s", err.Error())
+	}
+
+	fmt.Println(
+		lipgloss.NewStyle().Padding(0, 2).Render(
+			fmt.Sprintln("Purged", tui.StatusStyle.Render(item.Model), "permanently"),
+		),
+	)
+
+	return nil
+}
+
+// purgeExpiredTrash purges the models kept in the trash longer than the
+// configured retention. It is best effort, failures are retried on the next
+// run.
+func purgeExpiredTrash(cfg config.Config) {
+	if retention := cfg.TrashRetention(); retention > 0 {
+		_, _ = store.Open("").PurgeExpired(retention)
+	}
+}
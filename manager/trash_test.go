@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/store"
+)
+
+func TestIsLocalHost(t *testing.T) {
+	tests := map[string]bool{
+		"localhost":    true,
+		"127.0.0.1":    true,
+		"::1":          true,
+		"0.0.0.0":      true,
+		"192.168.1.20": false,
+		"gpu-box":      false,
+	}
+
+	for host, want := range tests {
+		if got := isLocalHost(host); got != want {
+			t.Errorf("isLocalHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+// newTestStore points OLLAMA_MODELS at a models dir holding a manifest for
+// name.
+func newTestStore(t *testing.T, name string) store.Store {
+	t.Helper()
+
+	s := store.Store{Dir: t.TempDir()}
+	t.Setenv("OLLAMA_MODELS", s.Dir)
+
+	if err := os.MkdirAll(filepath.Join(s.Dir, "blobs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.ManifestPath(name)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.ManifestPath(name), []byte(`{"schemaVersion":2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestTrashModelUnloadsFirst(t *testing.T) {
+	o, srv, _ := newTestAPI(t)
+	s := newTestStore(t, "llama3.2")
+	if err := srv.LoadModel("llama3.2", 0, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.trashModel("llama3.2:latest"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(srv.Running()) != 0 {
+		t.Fatal("trashed model still loaded")
+	}
+	if items, err := s.TrashItems(); err != nil || len(items) != 1 {
+		t.Fatalf("trash = %+v, %v", items, err)
+	}
+	// the server was not asked to delete it
+	if len(srv.Installed()) != 1 {
+		t.Fatal("model deleted through the API")
+	}
+}
+
+func TestTrashModelFallsBackToDelete(t *testing.T) {
+	o, srv, _ := newTestAPI(t)
+	s := newTestStore(t, "llama3.2")
+	if err := os.RemoveAll(filepath.Join(s.Dir, "blobs")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.trashModel("llama3.2:latest"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(srv.Installed()) != 0 {
+		t.Fatal("model not deleted through the API")
+	}
+	if items, _ := s.TrashItems(); len(items) != 0 {
+		t.Fatalf("trash = %+v", items)
+	}
+}
+
+func TestValidateTypedName(t *testing.T) {
+	validate := validateTypedName("llama3.2:latest")
+
+	tests := map[string]bool{
+		"llama3.2:latest":  true,
+		"":                 true,
+		"llama3.2":         false,
+		"llama3.2:latest ": false,
+		"qwen2.5:7b":       false,
+	}
+
+	for typed, ok := range tests {
+		if err := validate(typed); (err == nil) != ok {
+			t.Errorf("validate(%q) = %v, want ok %v", typed, err, ok)
+		}
+	}
+}
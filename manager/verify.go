@@ -0,0 +1,237 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	oldtea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/dustin/go-humanize"
+	"github.com/gaurav-gosain/ollamanager/events"
+	"github.com/gaurav-gosain/ollamanager/policy"
+	"github.com/gaurav-gosain/ollamanager/store"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/api"
+)
+
+// pullModel pulls modelName, showing the progress in the install view. It
+// returns the number of bytes downloaded.
+func (o OllamaAPI) pullModel(modelName string) (int64, error) {
+	ctx := context.Background()
+
+	if err := o.enforcePolicy(modelName); err != nil {
+		return 0, err
+	}
+	if err := o.ensureDiskBudget(modelName); err != nil {
+		return 0, err
+	}
+
+	req := &api.PullRequest{
+		Model: modelName,
+	}
+
+	transferred, err := runWithProgress(func(fn func(api.ProgressResponse) error) error {
+		return o.client.Pull(ctx, req, func(resp api.ProgressResponse) error {
+			o.bus.Emit(events.Event{
+				Type:     events.PULL_PROGRESS,
+				Model:    modelName,
+				Progress: &resp,
+			})
+			return fn(resp)
+		})
+	})
+	if err != nil {
+		err = fmt.Errorf("error pulling model: %s", err.Error())
+		o.emitError(modelName, err)
+		return transferred, err
+	}
+
+	o.emit(events.PULL_DONE, modelName)
+
+	return transferred, nil
+}
+
+// Verify re-hashes the blobs of the models (all installed models when names
+// is empty) in the local models directory and reports the missing or
+// corrupted layers of each one. The damaged models are then re-pulled,
+// without asking when repair is set. Corrupted blobs are removed first, as
+// the server would otherwise reuse them. Only models whose registry still
+// publishes the damaged layers are re-pulled, models created, imported or
+// copied locally are reported as unrepairable and left untouched.
+func Verify(names []string, repair bool, opts ...Option) error {
+	s := store.Open("")
+
+	for i, name := range names {
+		if err := validateModelName(name); err != nil {
+			return err
+		}
+		names[i] = parseModelName(name).String()
+	}
+
+	var damaged []store.Damage
+	_, err := runWithProgress(func(fn func(api.ProgressResponse) error) error {
+		d, err := s.Verify(names, fn)
+		if err != nil {
+			return err
+		}
+		damaged = d
+		return fn(api.ProgressResponse{Status: "success"})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify models: %s", err.Error())
+	}
+
+	style := lipgloss.NewStyle().Padding(0, 2)
+
+	if len(damaged) == 0 {
+		fmt.Println(style.Render("All blobs match their digests"))
+		return nil
+	}
+
+	var report strings.Builder
+	for _, damage := range damaged {
+		fmt.Fprintln(&report, tui.StatusStyle.Render(damage.Model))
+		for _, layer := range damage.Layers {
+			fmt.Fprintf(
+				&report,
+				"  %-7s %s (%s, %s)\n",
+				layer.Status,
+				layer.Digest,
+				layerKind(layer.MediaType),
+				humanize.Bytes(uint64(layer.Size)),
+			)
+		}
+	}
+	fmt.Println(style.Render(report.String()))
+
+	var repairable []store.Damage
+	var unrepairable strings.Builder
+	for _, damage := range damaged {
+		if err := checkRepairable(damage); err != nil {
+			fmt.Fprintf(&unrepairable, "%s can't be repaired: %s\n", tui.StatusStyle.Render(damage.Model), err.Error())
+			continue
+		}
+		repairable = append(repairable, damage)
+	}
+	if unrepairable.Len() > 0 {
+		fmt.Println(style.Render(unrepairable.String()))
+	}
+
+	selected, err := selectRepairs(repairable, repair)
+	if err != nil {
+		return err
+	}
+
+	ollamaAPI, err := newOllamaAPI(newRunOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	for _, damage := range repairable {
+		if !slices.Contains(selected, damage.Model) {
+			continue
+		}
+
+		for _, layer := range damage.Layers {
+			if layer.Status == store.CORRUPT {
+				if err := s.RemoveBlob(layer.Digest); err != nil {
+					return err
+				}
+			}
+		}
+
+		entry := ollamaAPI.beginHistory(string(tabs.UPDATE), damage.Model)
+		transferred, err := ollamaAPI.pullModel(damage.Model)
+		ollamaAPI.endHistory(entry, transferred, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(selected) < len(damaged) {
+		return fmt.Errorf("%d damaged models were not repaired", len(damaged)-len(selected))
+	}
+
+	return nil
+}
+
+// checkRepairable returns why re-pulling the model would not restore its
+// damaged layers: the registry does not know the model (e.g. it was created,
+// imported or copied locally), or publishes different layers under its name
+// (a local model named like a library one, or a since updated model).
+func checkRepairable(damage store.Damage) error {
+	remote, err := policy.RemoteManifest(damage.Model)
+	if err != nil {
+		return fmt.Errorf("not found in its registry (%s)", err.Error())
+	}
+
+	published := map[string]bool{}
+	for _, layer := range remote.Blobs() {
+		published[layer.Digest] = true
+	}
+
+	for _, layer := range damage.Layers {
+		if !published[layer.Digest] {
+			return fmt.Errorf("its registry does not publish layer %s", layer.Digest)
+		}
+	}
+
+	return nil
+}
+
+// selectRepairs returns the damaged models to re-pull: all of them when
+// repair is set, the ones picked by the user otherwise. Nothing is re-pulled
+// without asking when stdin is not a terminal.
+func selectRepairs(damaged []store.Damage, repair bool) ([]string, error) {
+	var models []string
+	for _, damage := range damaged {
+		models = append(models, damage.Model)
+	}
+
+	if repair {
+		return models, nil
+	}
+
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return nil, nil
+	}
+
+	options := huh.NewOptions(models...)
+	for i := range options {
+		options[i] = options[i].Selected(true)
+	}
+
+	var selected []string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Re-pull the damaged models?").
+				Description("Unselect all to skip.").
+				Options(options...).
+				Value(&selected),
+		),
+	).WithProgramOptions(oldtea.WithAltScreen())
+
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+
+	return selected, nil
+}
+
+// layerKind shortens a layer media type, e.g. "model" for
+// application/vnd.ollama.image.model.
+func layerKind(mediaType string) string {
+	if kind, ok := strings.CutPrefix(mediaType, "application/vnd.ollama.image."); ok {
+		return kind
+	}
+
+	return "config"
+}
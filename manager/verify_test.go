@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gaurav-gosain/ollamanager/ollamatest"
+	"github.com/gaurav-gosain/ollamanager/policy"
+	"github.com/gaurav-gosain/ollamanager/store"
+)
+
+func TestCheckRepairable(t *testing.T) {
+	srv := ollamatest.NewServer()
+	defer srv.Close()
+	srv.AddRegistryModel(ollamatest.RegistryModel{Name: "llama3.2", Size: 100})
+
+	registryURL := policy.RegistryURL
+	defer func() { policy.RegistryURL = registryURL }()
+	policy.RegistryURL = srv.RegistryURL()
+
+	damaged := func(model, digest string) store.Damage {
+		return store.Damage{
+			Model: model,
+			Layers: []store.DamagedLayer{{
+				Layer:  store.Layer{Digest: digest},
+				Status: store.CORRUPT,
+			}},
+		}
+	}
+	published := fmt.Sprintf("sha256:%064x", 100)
+
+	tests := []struct {
+		name   string
+		damage store.Damage
+		ok     bool
+	}{
+		{"pulled", damaged("llama3.2:latest", published), true},
+		{"local with a library name", damaged("llama3.2:latest", fmt.Sprintf("sha256:%064x", 7)), false},
+		{"copied", damaged("team-model:latest", published), false},
+	}
+
+	for _, tt := range tests {
+		if err := checkRepairable(tt.damage); (err == nil) != tt.ok {
+			t.Errorf("%s: checkRepairable = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
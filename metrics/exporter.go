@@ -0,0 +1,260 @@
+// Package metrics exposes Ollama model state in the Prometheus text format.
+package metrics
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/history"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/ollama/ollama/api"
+)
+
+// Exporter periodically polls Ollama and serves the last snapshot on
+// /metrics. When a poll fails the model metrics are dropped rather than
+// served stale, and ollama_up is 0.
+type Exporter struct {
+	client      *api.Client
+	historyPath string
+
+	mu        sync.RWMutex
+	running   []api.ProcessModelResponse
+	installed []api.ListModelResponse
+	pulls     []history.Entry
+	scrapeErr error
+	scrapedAt time.Time
+}
+
+// NewExporter returns an exporter for the Ollama server behind client. Pull
+// metrics are derived from the history log at historyPath, which may be empty
+// to disable them.
+func NewExporter(client *api.Client, historyPath string) *Exporter {
+	return &Exporter{
+		client:      client,
+		historyPath: historyPath,
+	}
+}
+
+// Poll refreshes the snapshot once.
+func (e *Exporter) Poll(ctx context.Context) error {
+	var running []api.ProcessModelResponse
+	var installed []api.ListModelResponse
+
+	ps, err := e.client.ListRunning(ctx)
+	if err == nil {
+		var list *api.ListResponse
+		list, err = e.client.List(ctx)
+		if err == nil {
+			running = ps.Models
+			installed = list.Models
+		}
+	}
+
+	var pulls []history.Entry
+	if e.historyPath != "" {
+		entries, _ := history.Read(e.historyPath)
+		for _, entry := range entries {
+			if entry.Action == string(tabs.INSTALL) || entry.Action == string(tabs.UPDATE) {
+				pulls = append(pulls, entry)
+			}
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.running = running
+	e.installed = installed
+	e.pulls = pulls
+	e.scrapeErr = err
+	e.scrapedAt = time.Now()
+
+	return err
+}
+
+// Run polls every interval until the context is cancelled.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = e.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.Write(w)
+}
+
+// Write renders the current snapshot in the Prometheus text format.
+func (e *Exporter) Write(w io.Writer) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	up := 1
+	if e.scrapeErr != nil || e.scrapedAt.IsZero() {
+		up = 0
+	}
+	gauge(w, "ollama_up", "Whether the last poll of the Ollama server succeeded.")
+	sample(w, "ollama_up", nil, float64(up))
+
+	if up == 0 {
+		// no current model state, only the pull history is still known
+		e.writePulls(w)
+		return
+	}
+
+	running := slices.SortedFunc(slices.Values(e.running), func(a, b api.ProcessModelResponse) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	gauge(w, "ollama_loaded_models", "Number of models currently loaded in memory.")
+	sample(w, "ollama_loaded_models", nil, float64(len(running)))
+
+	gauge(w, "ollama_loaded_model_size_bytes", "Memory used by a loaded model.")
+	for _, m := range running {
+		sample(w, "ollama_loaded_model_size_bytes", modelLabels(m.Name), float64(m.Size))
+	}
+
+	gauge(w, "ollama_loaded_model_size_vram_bytes", "GPU memory used by a loaded model.")
+	for _, m := range running {
+		sample(w, "ollama_loaded_model_size_vram_bytes", modelLabels(m.Name), float64(m.SizeVRAM))
+	}
+
+	gauge(w, "ollama_loaded_model_expires_in_seconds", "Seconds until a loaded model is unloaded.")
+	for _, m := range running {
+		sample(w, "ollama_loaded_model_expires_in_seconds", modelLabels(m.Name), max(time.Until(m.ExpiresAt).Seconds(), 0))
+	}
+
+	var diskBytes int64
+	for _, m := range e.installed {
+		diskBytes += m.Size
+	}
+
+	gauge(w, "ollama_installed_models", "Number of installed models.")
+	sample(w, "ollama_installed_models", nil, float64(len(e.installed)))
+
+	gauge(w, "ollama_installed_bytes", "Total size of all installed models.")
+	sample(w, "ollama_installed_bytes", nil, float64(diskBytes))
+
+	e.writePulls(w)
+}
+
+// writePulls renders the pull metrics derived from the history log.
+func (e *Exporter) writePulls(w io.Writer) {
+	type pullStats struct {
+		count, failed  int
+		seconds, bytes float64
+	}
+	stats := map[string]*pullStats{}
+	for _, p := range e.pulls {
+		s, ok := stats[p.Model]
+		if !ok {
+			s = &pullStats{}
+			stats[p.Model] = s
+		}
+		if p.Outcome != history.SUCCESS {
+			s.failed++
+			continue
+		}
+		s.count++
+		s.seconds += p.Duration.Seconds()
+		s.bytes += float64(p.Bytes)
+	}
+	models := slices.Sorted(maps.Keys(stats))
+
+	counter(w, "ollamanager_pulls_total", "Pulls done through ollamanager by outcome.")
+	for _, model := range models {
+		sample(w, "ollamanager_pulls_total", append(modelLabels(model), "outcome", "success"), float64(stats[model].count))
+		sample(w, "ollamanager_pulls_total", append(modelLabels(model), "outcome", "failure"), float64(stats[model].failed))
+	}
+
+	counter(w, "ollamanager_pull_bytes_total", "Bytes downloaded by successful pulls done through ollamanager.")
+	for _, model := range models {
+		sample(w, "ollamanager_pull_bytes_total", modelLabels(model), stats[model].bytes)
+	}
+
+	fmt.Fprintln(w, "# HELP ollamanager_pull_duration_seconds Duration of successful pulls done through ollamanager.")
+	fmt.Fprintln(w, "# TYPE ollamanager_pull_duration_seconds summary")
+	for _, model := range models {
+		sample(w, "ollamanager_pull_duration_seconds_sum", modelLabels(model), stats[model].seconds)
+		sample(w, "ollamanager_pull_duration_seconds_count", modelLabels(model), float64(stats[model].count))
+	}
+}
+
+// Serve polls Ollama every interval and serves the metrics on addr until the
+// context is cancelled.
+func Serve(ctx context.Context, addr string, interval time.Duration, e *Exporter) error {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", e)
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	go e.Run(ctx, interval)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	err := server.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+
+	return err
+}
+
+func modelLabels(model string) []string {
+	return []string{"model", model}
+}
+
+func gauge(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func counter(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+}
+
+// labelEscaper escapes label values as the text format expects, which only
+// knows backslash, double quote and line feed escapes.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// sample writes a single metric line, labels are given as key/value pairs.
+func sample(w io.Writer, name string, labels []string, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, `%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1]))
+		}
+		b.WriteByte('}')
+	}
+
+	fmt.Fprintf(w, "%s %g\n", b.String(), value)
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/ollamatest"
+)
+
+func TestSampleEscapesLabels(t *testing.T) {
+	var b strings.Builder
+	sample(&b, "m", []string{"model", "a\\b\"c\nd é"}, 1)
+
+	if got, want := b.String(), `m{model="a\\b\"c\nd é"} 1`+"\n"; got != want {
+		t.Fatalf("sample = %q, want %q", got, want)
+	}
+}
+
+func TestExporterDropsSnapshotOnFailedPoll(t *testing.T) {
+	srv := ollamatest.NewServer()
+	defer srv.Close()
+	srv.AddModel(ollamatest.Model("llama3.2", 2<<30))
+	if err := srv.LoadModel("llama3.2", 1<<30, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewExporter(srv.Client(), "")
+	if err := e.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	e.Write(&b)
+	for _, line := range []string{
+		"ollama_up 1\n",
+		"ollama_loaded_models 1\n",
+		`ollama_loaded_model_size_bytes{model="llama3.2:latest"}`,
+		"ollama_installed_models 1\n",
+	} {
+		if !strings.Contains(b.String(), line) {
+			t.Fatalf("metrics miss %q:\n%s", line, b.String())
+		}
+	}
+
+	srv.Close()
+	if err := e.Poll(context.Background()); err == nil {
+		t.Fatal("poll of a stopped server succeeded")
+	}
+
+	b.Reset()
+	e.Write(&b)
+	if !strings.Contains(b.String(), "ollama_up 0\n") {
+		t.Fatalf("ollama_up not 0:\n%s", b.String())
+	}
+	if strings.Contains(b.String(), "llama3.2") || strings.Contains(b.String(), "ollama_installed_models") {
+		t.Fatalf("stale snapshot served:\n%s", b.String())
+	}
+}
@@ -0,0 +1,170 @@
+package ollamatest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// LibraryModel is a model listed on the fake ollama.com library page.
+type LibraryModel struct {
+	Name        string
+	Description string
+	Pulls       string
+	Updated     string
+	Capability  []string
+	Tags        []string
+}
+
+// AddLibraryModel lists a model on the fake library and tags pages.
+func (s *Server) AddLibraryModel(model LibraryModel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.library = append(s.library, model)
+}
+
+func (s *Server) handleLibrary(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	library := slices.Clone(s.library)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	fmt.Fprint(w, `<html><body><div id="repo"><ul>`)
+	for _, model := range library {
+		fmt.Fprintf(w,
+			`<li><a href="/library/%[1]s"><div><h2><div><span>%[1]s</span></div></h2><p>%[2]s</p><div>`,
+			html.EscapeString(model.Name),
+			html.EscapeString(model.Description),
+		)
+		for _, capability := range model.Capability {
+			fmt.Fprintf(w, `<span>%s</span>`, html.EscapeString(capability))
+		}
+		fmt.Fprintf(w,
+			`</div></div></a><p><span><span>%s</span> Pulls</span><span><span>%d</span> Tags</span><span><span></span><span>%s</span></span></p></li>`,
+			html.EscapeString(model.Pulls),
+			len(model.Tags),
+			html.EscapeString(model.Updated),
+		)
+	}
+	fmt.Fprint(w, `</ul></div></body></html>`)
+}
+
+func (s *Server) handleLibraryTags(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("model")
+
+	s.mu.Lock()
+	idx := slices.IndexFunc(s.library, func(m LibraryModel) bool { return m.Name == name })
+	var tags []string
+	if idx >= 0 {
+		tags = slices.Clone(s.library[idx].Tags)
+	}
+	s.mu.Unlock()
+
+	if idx < 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	for _, tag := range tags {
+		// the tags scraper works line by line, so keep one link per line
+		fmt.Fprintf(w, "<a href=\"/library/%s:%s\">%s</a>\n", name, tag, tag)
+	}
+}
+
+// RegistryModel is a model tag published on the fake registry, with the
+// details the pull policy is checked against.
+type RegistryModel struct {
+	Name          string
+	ParameterSize string
+	Quantization  string
+	Size          int64
+}
+
+// AddRegistryModel publishes a manifest and config blob for the model on the
+// fake registry. Names without a tag get "latest".
+func (s *Server) AddRegistryModel(model RegistryModel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	model.Name = normalize(model.Name)
+	s.registry[model.Name] = model
+}
+
+func (s *Server) registryModel(r *http.Request) (RegistryModel, bool) {
+	name := r.PathValue("model")
+	if namespace := r.PathValue("namespace"); namespace != "library" {
+		name = namespace + "/" + name
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tag := r.PathValue("tag"); tag != "" {
+		model, ok := s.registry[name+":"+tag]
+		return model, ok
+	}
+
+	// blobs are looked up by digest
+	for _, model := range s.registry {
+		if strings.HasPrefix(model.Name, name+":") && registryConfigDigest(model) == r.PathValue("digest") {
+			return model, true
+		}
+	}
+
+	return RegistryModel{}, false
+}
+
+func registryConfig(model RegistryModel) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"model_format": "gguf",
+		"model_type":   model.ParameterSize,
+		"file_type":    model.Quantization,
+	})
+	return data
+}
+
+func registryConfigDigest(model RegistryModel) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(registryConfig(model)))
+}
+
+func (s *Server) handleRegistryManifest(w http.ResponseWriter, r *http.Request) {
+	model, ok := s.registryModel(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	config := registryConfig(model)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.docker.distribution.manifest.v2+json",
+		"config": map[string]any{
+			"mediaType": "application/vnd.docker.container.image.v1+json",
+			"digest":    registryConfigDigest(model),
+			"size":      len(config),
+		},
+		"layers": []map[string]any{{
+			"mediaType": "application/vnd.ollama.image.model",
+			"digest":    fmt.Sprintf("sha256:%064x", model.Size),
+			"size":      model.Size,
+		}},
+	})
+}
+
+func (s *Server) handleRegistryBlob(w http.ResponseWriter, r *http.Request) {
+	model, ok := s.registryModel(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(registryConfig(model))
+}
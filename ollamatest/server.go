@@ -0,0 +1,812 @@
+// Package ollamatest provides an in-process fake of the Ollama HTTP API and
+// the ollama.com library pages, so code built on ollamanager can be exercised
+// end-to-end without a real server or network access.
+//
+//	srv := ollamatest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddModel(ollamatest.Model("llama3.2:latest", 2<<30))
+//	os.Setenv("OLLAMA_HOST", srv.URL)
+package ollamatest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// PullStep is a single scripted line of a pull stream. When Err is set the
+// stream is terminated with that error instead of sending Progress.
+type PullStep struct {
+	Progress api.ProgressResponse
+	Err      string
+	Delay    time.Duration
+}
+
+// Server is a fake Ollama server backed by httptest. All methods are safe for
+// concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	installed map[string]api.ListModelResponse
+	running   map[string]api.ProcessModelResponse
+	shows     map[string]api.ShowResponse
+	pulls     map[string][]PullStep
+	responses map[string]string
+	blobs     map[string]int64
+	library   []LibraryModel
+	registry  map[string]RegistryModel
+	requests  []string
+}
+
+// NewServer starts a fake server with no installed or running models. The
+// caller must call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		installed: map[string]api.ListModelResponse{},
+		running:   map[string]api.ProcessModelResponse{},
+		shows:     map[string]api.ShowResponse{},
+		pulls:     map[string][]PullStep{},
+		responses: map[string]string{},
+		blobs:     map[string]int64{},
+		registry:  map[string]RegistryModel{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("HEAD /{$}", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "Ollama is running")
+	})
+	mux.HandleFunc("GET /api/version", s.handleVersion)
+	mux.HandleFunc("GET /api/tags", s.handleList)
+	mux.HandleFunc("GET /api/ps", s.handlePs)
+	mux.HandleFunc("POST /api/pull", s.handlePull)
+	mux.HandleFunc("DELETE /api/delete", s.handleDelete)
+	mux.HandleFunc("POST /api/generate", s.handleGenerate)
+	mux.HandleFunc("POST /api/chat", s.handleChat)
+	mux.HandleFunc("POST /api/show", s.handleShow)
+	mux.HandleFunc("POST /api/embed", s.handleEmbed)
+	mux.HandleFunc("POST /api/create", s.handleCreate)
+	mux.HandleFunc("POST /api/copy", s.handleCopy)
+	mux.HandleFunc("POST /api/push", s.handlePush)
+	mux.HandleFunc("HEAD /api/blobs/{digest}", s.handleBlobExists)
+	mux.HandleFunc("POST /api/blobs/{digest}", s.handleCreateBlob)
+	mux.HandleFunc("GET /library", s.handleLibrary)
+	mux.HandleFunc("GET /library/{model}/tags", s.handleLibraryTags)
+	mux.HandleFunc("GET /v2/{namespace}/{model}/manifests/{tag}", s.handleRegistryManifest)
+	mux.HandleFunc("GET /v2/{namespace}/{model}/blobs/{digest}", s.handleRegistryBlob)
+
+	s.Server = httptest.NewServer(s.record(mux))
+
+	return s
+}
+
+// Client returns an Ollama API client talking to the fake server.
+func (s *Server) Client() *api.Client {
+	base, _ := url.Parse(s.URL)
+	return api.NewClient(base, s.Server.Client())
+}
+
+// LibraryURL is the fake equivalent of https://ollama.com/library, suitable
+// for tui.LibraryURL.
+func (s *Server) LibraryURL() string {
+	return s.URL + "/library"
+}
+
+// RegistryURL is the fake equivalent of https://registry.ollama.ai, suitable
+// for policy.RegistryURL.
+func (s *Server) RegistryURL() string {
+	return s.URL
+}
+
+// Requests returns every request received so far as "METHOD /path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+// Model returns a minimal installed model description with the given name and
+// size in bytes.
+func Model(name string, size int64) api.ListModelResponse {
+	return api.ListModelResponse{
+		Name:       name,
+		Model:      name,
+		ModifiedAt: time.Now(),
+		Size:       size,
+		Digest:     fmt.Sprintf("%064x", len(name)*7919+int(size%7919)),
+		Details: api.ModelDetails{
+			Format:            "gguf",
+			Family:            "llama",
+			Families:          []string{"llama"},
+			ParameterSize:     "8B",
+			QuantizationLevel: "Q4_0",
+		},
+	}
+}
+
+// AddModel installs the model. Names without a tag get ":latest", like the
+// real server lists them; the same goes for every method taking a name.
+func (s *Server) AddModel(model api.ListModelResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	model.Name = normalize(model.Name)
+	model.Model = normalize(firstNonEmpty(model.Model, model.Name))
+	s.installed[model.Name] = model
+}
+
+func (s *Server) RemoveModel(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name = normalize(name)
+	delete(s.installed, name)
+	delete(s.running, name)
+}
+
+// Installed returns the installed models sorted by name.
+func (s *Server) Installed() []api.ListModelResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return sortedValues(s.installed)
+}
+
+// Running returns the loaded models sorted by name.
+func (s *Server) Running() []api.ProcessModelResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return sortedValues(s.running)
+}
+
+// LoadModel marks an installed model as running until expiresAt. A zero
+// expiresAt keeps it loaded indefinitely.
+func (s *Server) LoadModel(name string, sizeVRAM int64, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name = normalize(name)
+	model, ok := s.installed[name]
+	if !ok {
+		return fmt.Errorf("model %q not found", name)
+	}
+
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(100 * 365 * 24 * time.Hour)
+	}
+
+	s.running[name] = api.ProcessModelResponse{
+		Name:      model.Name,
+		Model:     model.Model,
+		Size:      model.Size,
+		Digest:    model.Digest,
+		Details:   model.Details,
+		ExpiresAt: expiresAt,
+		SizeVRAM:  sizeVRAM,
+	}
+
+	return nil
+}
+
+// SetShow overrides the Show response of a model. Without an override a
+// response is derived from the installed model.
+func (s *Server) SetShow(name string, show api.ShowResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shows[normalize(name)] = show
+}
+
+// SetResponse sets the text streamed back by generate and chat for a model.
+func (s *Server) SetResponse(name, response string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[normalize(name)] = response
+}
+
+// ScriptPull sets the progress lines streamed when the model is pulled. If
+// the script finishes with a "success" status the model is installed.
+func (s *Server) ScriptPull(name string, steps ...PullStep) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pulls[normalize(name)] = steps
+}
+
+// FailPull makes pulling the model fail after the manifest step.
+func (s *Server) FailPull(name, errMsg string) {
+	s.ScriptPull(name,
+		PullStep{Progress: api.ProgressResponse{Status: "pulling manifest"}},
+		PullStep{Err: errMsg},
+	)
+}
+
+// DefaultPullScript is the script used for models without one: a manifest
+// step, a single layer downloaded in four chunks and the closing steps.
+func DefaultPullScript(size int64) []PullStep {
+	digest := fmt.Sprintf("sha256:%064x", size)
+	steps := []PullStep{
+		{Progress: api.ProgressResponse{Status: "pulling manifest"}},
+	}
+	for i := int64(0); i <= 4; i++ {
+		steps = append(steps, PullStep{Progress: api.ProgressResponse{
+			Status:    "pulling " + digest[7:19],
+			Digest:    digest,
+			Total:     size,
+			Completed: size * i / 4,
+		}})
+	}
+
+	return append(steps,
+		PullStep{Progress: api.ProgressResponse{Status: "verifying sha256 digest"}},
+		PullStep{Progress: api.ProgressResponse{Status: "writing manifest"}},
+		PullStep{Progress: api.ProgressResponse{Status: "removing any unused layers"}},
+		PullStep{Progress: api.ProgressResponse{Status: "success"}},
+	)
+}
+
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"version": "0.0.0-ollamatest"})
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, api.ListResponse{Models: s.Installed()})
+}
+
+func (s *Server) handlePs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	for name, model := range s.running {
+		if time.Now().After(model.ExpiresAt) {
+			delete(s.running, name)
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, api.ProcessResponse{Models: s.Running()})
+}
+
+func (s *Server) handlePull(w http.ResponseWriter, r *http.Request) {
+	var req api.PullRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	name := normalize(firstNonEmpty(req.Model, req.Name))
+
+	s.mu.Lock()
+	steps, ok := s.pulls[name]
+	s.mu.Unlock()
+	if !ok {
+		steps = DefaultPullScript(1 << 30)
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+
+	var last api.ProgressResponse
+	for _, step := range steps {
+		if step.Delay > 0 {
+			select {
+			case <-time.After(step.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if step.Err != "" {
+			_ = enc.Encode(map[string]string{"error": step.Err})
+			return
+		}
+
+		last = step.Progress
+		_ = enc.Encode(step.Progress)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	if last.Status == "success" {
+		s.mu.Lock()
+		if _, exists := s.installed[name]; !exists {
+			var size int64
+			for _, step := range steps {
+				size = max(size, step.Progress.Total)
+			}
+			s.installed[name] = Model(name, size)
+		} else {
+			model := s.installed[name]
+			model.ModifiedAt = time.Now()
+			s.installed[name] = model
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	var req api.DeleteRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	name := normalize(firstNonEmpty(req.Model, req.Name))
+
+	s.mu.Lock()
+	_, ok := s.installed[name]
+	delete(s.installed, name)
+	delete(s.running, name)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("model '%s' not found", name))
+	}
+}
+
+func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var req api.GenerateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	name := normalize(req.Model)
+
+	s.mu.Lock()
+	_, ok := s.installed[name]
+	response := s.responses[name]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("model '%s' not found, try pulling it first", name))
+		return
+	}
+
+	keepAlive := 5 * time.Minute
+	if req.KeepAlive != nil {
+		keepAlive = req.KeepAlive.Duration
+	}
+
+	switch {
+	case keepAlive == 0:
+		s.mu.Lock()
+		delete(s.running, name)
+		s.mu.Unlock()
+	case keepAlive < 0:
+		_ = s.LoadModel(name, 0, time.Time{})
+	default:
+		_ = s.LoadModel(name, 0, time.Now().Add(keepAlive))
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+
+	if req.Prompt == "" {
+		doneReason := "load"
+		if keepAlive == 0 {
+			doneReason = "unload"
+		}
+		_ = enc.Encode(api.GenerateResponse{
+			Model:      name,
+			CreatedAt:  time.Now(),
+			Done:       true,
+			DoneReason: doneReason,
+		})
+		return
+	}
+
+	if response == "" {
+		response = "Hello from ollamatest!"
+	}
+
+	words := strings.SplitAfter(response, " ")
+	for _, word := range words {
+		_ = enc.Encode(api.GenerateResponse{
+			Model:     name,
+			CreatedAt: time.Now(),
+			Response:  word,
+		})
+	}
+	_ = enc.Encode(api.GenerateResponse{
+		Model:      name,
+		CreatedAt:  time.Now(),
+		Done:       true,
+		DoneReason: "stop",
+		Metrics: api.Metrics{
+			TotalDuration:      time.Duration(len(words)+2) * time.Millisecond,
+			LoadDuration:       time.Millisecond,
+			PromptEvalCount:    len(strings.Fields(req.Prompt)),
+			PromptEvalDuration: time.Millisecond,
+			EvalCount:          len(words),
+			EvalDuration:       time.Duration(len(words)) * time.Millisecond,
+		},
+	})
+}
+
+func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		api.ChatRequest
+		// Format is a JSON schema on newer servers, not only "json". The
+		// response is sent whatever the format.
+		Format json.RawMessage `json:"format"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	name := normalize(req.Model)
+
+	s.mu.Lock()
+	_, ok := s.installed[name]
+	response := s.responses[name]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("model '%s' not found, try pulling it first", name))
+		return
+	}
+
+	_ = s.LoadModel(name, 0, time.Now().Add(5*time.Minute))
+
+	if response == "" {
+		response = "Hello from ollamatest!"
+	}
+
+	var promptTokens int
+	for _, message := range req.Messages {
+		promptTokens += len(strings.Fields(message.Content))
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+
+	// with tools, answer a user message by calling the first tool with its
+	// required arguments, and a tool result with the response
+	if len(req.Tools) > 0 && len(req.Messages) > 0 && req.Messages[len(req.Messages)-1].Role == "user" {
+		tool := req.Tools[0].Function
+		arguments := api.ToolCallFunctionArguments{}
+		for _, name := range tool.Parameters.Required {
+			arguments[name] = "test"
+		}
+
+		_ = enc.Encode(api.ChatResponse{
+			Model:     name,
+			CreatedAt: time.Now(),
+			Message: api.Message{
+				Role: "assistant",
+				ToolCalls: []api.ToolCall{{
+					Function: api.ToolCallFunction{Name: tool.Name, Arguments: arguments},
+				}},
+			},
+			Done:       true,
+			DoneReason: "stop",
+		})
+		return
+	}
+
+	words := strings.SplitAfter(response, " ")
+	done := api.ChatResponse{
+		Model:      name,
+		CreatedAt:  time.Now(),
+		Message:    api.Message{Role: "assistant"},
+		Done:       true,
+		DoneReason: "stop",
+		Metrics: api.Metrics{
+			TotalDuration:      time.Duration(len(words)+2) * time.Millisecond,
+			LoadDuration:       time.Millisecond,
+			PromptEvalCount:    promptTokens,
+			PromptEvalDuration: time.Millisecond,
+			EvalCount:          len(words),
+			EvalDuration:       time.Duration(len(words)) * time.Millisecond,
+		},
+	}
+
+	if req.Stream != nil && !*req.Stream {
+		done.Message.Content = response
+		_ = enc.Encode(done)
+		return
+	}
+
+	for _, word := range words {
+		_ = enc.Encode(api.ChatResponse{
+			Model:     name,
+			CreatedAt: time.Now(),
+			Message:   api.Message{Role: "assistant", Content: word},
+		})
+	}
+	_ = enc.Encode(done)
+}
+
+// embeddingDimensions is the length of the vectors returned by /api/embed.
+const embeddingDimensions = 16
+
+func (s *Server) handleEmbed(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Model string `json:"model"`
+		Input any    `json:"input"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	name := normalize(req.Model)
+
+	s.mu.Lock()
+	_, ok := s.installed[name]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("model \"%s\" not found, try pulling it first", name))
+		return
+	}
+
+	var inputs []string
+	switch input := req.Input.(type) {
+	case string:
+		inputs = []string{input}
+	case []any:
+		for _, i := range input {
+			text, _ := i.(string)
+			inputs = append(inputs, text)
+		}
+	}
+
+	// hash every word into a bucket so texts sharing words are similar
+	resp := api.EmbedResponse{Model: name}
+	for _, input := range inputs {
+		vector := make([]float32, embeddingDimensions)
+		for _, word := range strings.Fields(strings.ToLower(input)) {
+			sum := sha256.Sum256([]byte(word))
+			vector[int(sum[0])%embeddingDimensions]++
+		}
+		resp.Embeddings = append(resp.Embeddings, vector)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleShow(w http.ResponseWriter, r *http.Request) {
+	var req api.ShowRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	name := normalize(firstNonEmpty(req.Model, req.Name))
+
+	s.mu.Lock()
+	model, ok := s.installed[name]
+	show, hasShow := s.shows[name]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("model '%s' not found", name))
+		return
+	}
+
+	if !hasShow {
+		show = api.ShowResponse{
+			Modelfile:  fmt.Sprintf("FROM %s\nTEMPLATE {{ .Prompt }}\n", name),
+			Parameters: "stop \"<|eot_id|>\"",
+			Template:   "{{ .Prompt }}",
+			Details:    model.Details,
+			ModifiedAt: model.ModifiedAt,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, show)
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req api.CreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	name := normalize(firstNonEmpty(req.Model, req.Name))
+
+	var from string
+	for _, line := range strings.Split(req.Modelfile, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.EqualFold(fields[0], "FROM") {
+			from = fields[1]
+		}
+	}
+	if from == "" {
+		writeError(w, http.StatusBadRequest, "no FROM line for the model was specified")
+		return
+	}
+
+	s.mu.Lock()
+	base, ok := s.installed[normalize(from)]
+	if blobSize, isBlob := s.blobs[strings.TrimPrefix(from, "@")]; isBlob {
+		base, ok = Model(name, blobSize), true
+		base.Digest = strings.TrimPrefix(from, "@sha256:")
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("model '%s' not found", from))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	for _, status := range []string{
+		"using existing layer sha256:" + base.Digest,
+		"creating new layer sha256:" + fmt.Sprintf("%064x", len(req.Modelfile)),
+		"writing manifest",
+		"success",
+	} {
+		_ = enc.Encode(api.ProgressResponse{Status: status})
+	}
+
+	model := base
+	model.Name = name
+	model.Model = name
+	model.ModifiedAt = time.Now()
+	model.Digest = fmt.Sprintf("%064x", len(name)*31+len(req.Modelfile))
+	s.AddModel(model)
+}
+
+func (s *Server) handleCopy(w http.ResponseWriter, r *http.Request) {
+	var req api.CopyRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	source, destination := normalize(req.Source), normalize(req.Destination)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	model, ok := s.installed[source]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("model '%s' not found", req.Source))
+		return
+	}
+
+	model.Name = destination
+	model.Model = destination
+	model.ModifiedAt = time.Now()
+	s.installed[destination] = model
+}
+
+func (s *Server) handlePush(w http.ResponseWriter, r *http.Request) {
+	var req api.PushRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	name := normalize(firstNonEmpty(req.Model, req.Name))
+
+	s.mu.Lock()
+	model, ok := s.installed[name]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("model '%s' not found", name))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+
+	_ = enc.Encode(api.ProgressResponse{Status: "retrieving manifest"})
+	digest := "sha256:" + model.Digest
+	for i := int64(0); i <= 2; i++ {
+		_ = enc.Encode(api.ProgressResponse{
+			Status:    "pushing " + model.Digest[:12],
+			Digest:    digest,
+			Total:     model.Size,
+			Completed: model.Size * i / 2,
+		})
+	}
+	_ = enc.Encode(api.ProgressResponse{Status: "pushing manifest"})
+	_ = enc.Encode(api.ProgressResponse{Status: "success"})
+}
+
+// Blobs returns the digests and sizes of every uploaded blob.
+func (s *Server) Blobs() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return maps.Clone(s.blobs)
+}
+
+func (s *Server) handleBlobExists(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	_, ok := s.blobs[r.PathValue("digest")]
+	s.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleCreateBlob(w http.ResponseWriter, r *http.Request) {
+	digest := r.PathValue("digest")
+
+	h := sha256.New()
+	n, err := io.Copy(h, r.Body)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if fmt.Sprintf("sha256:%x", h.Sum(nil)) != digest {
+		writeError(w, http.StatusBadRequest, "digest mismatch")
+		return
+	}
+
+	s.mu.Lock()
+	s.blobs[digest] = n
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// normalize appends the implicit ":latest" tag like the real server does.
+func normalize(name string) string {
+	if name != "" && !strings.Contains(name, ":") {
+		return name + ":latest"
+	}
+
+	return name
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func sortedValues[T any](m map[string]T) []T {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	values := make([]T, 0, len(m))
+	for _, k := range keys {
+		values = append(values, m[k])
+	}
+
+	return values
+}
@@ -0,0 +1,241 @@
+package ollamatest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+func newServer(t *testing.T) (*Server, *api.Client) {
+	t.Helper()
+
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	return srv, srv.Client()
+}
+
+func TestList(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddModel(Model("llama3.2", 2<<30))
+	srv.AddModel(Model("qwen2.5:7b", 4<<30))
+
+	list, err := client.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, model := range list.Models {
+		names = append(names, model.Name)
+	}
+	if got, want := strings.Join(names, ","), "llama3.2:latest,qwen2.5:7b"; got != want {
+		t.Fatalf("models = %s, want %s", got, want)
+	}
+}
+
+func TestPs(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddModel(Model("llama3.2", 2<<30))
+	srv.AddModel(Model("qwen2.5:7b", 4<<30))
+
+	if err := srv.LoadModel("llama3.2", 1<<30, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.LoadModel("qwen2.5:7b", 0, time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.LoadModel("missing", 0, time.Time{}); err == nil {
+		t.Fatal("loading a model that is not installed succeeded")
+	}
+
+	running, err := client.ListRunning(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the expired model is unloaded
+	if len(running.Models) != 1 || running.Models[0].Name != "llama3.2:latest" || running.Models[0].SizeVRAM != 1<<30 {
+		t.Fatalf("running = %+v", running.Models)
+	}
+}
+
+func TestPull(t *testing.T) {
+	srv, client := newServer(t)
+
+	var statuses []string
+	err := client.Pull(context.Background(), &api.PullRequest{Model: "llama3.2"}, func(resp api.ProgressResponse) error {
+		statuses = append(statuses, resp.Status)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := statuses[len(statuses)-1]; got != "success" {
+		t.Fatalf("last status = %q, want success", got)
+	}
+	if installed := srv.Installed(); len(installed) != 1 || installed[0].Name != "llama3.2:latest" {
+		t.Fatalf("installed = %+v", installed)
+	}
+}
+
+func TestScriptPullNormalizesName(t *testing.T) {
+	srv, client := newServer(t)
+	srv.ScriptPull("llama3",
+		PullStep{Progress: api.ProgressResponse{Status: "pulling manifest"}},
+		PullStep{Progress: api.ProgressResponse{Status: "scripted"}},
+		PullStep{Progress: api.ProgressResponse{Status: "success"}},
+	)
+
+	var statuses []string
+	err := client.Pull(context.Background(), &api.PullRequest{Model: "llama3:latest"}, func(resp api.ProgressResponse) error {
+		statuses = append(statuses, resp.Status)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(statuses, ","), "pulling manifest,scripted,success"; got != want {
+		t.Fatalf("statuses = %s, want %s", got, want)
+	}
+}
+
+func TestFailPull(t *testing.T) {
+	srv, client := newServer(t)
+	srv.FailPull("llama3", "pull model manifest: file does not exist")
+
+	err := client.Pull(context.Background(), &api.PullRequest{Model: "llama3"}, func(api.ProgressResponse) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "file does not exist") {
+		t.Fatalf("err = %v, want the scripted error", err)
+	}
+	if installed := srv.Installed(); len(installed) != 0 {
+		t.Fatalf("failed pull installed %+v", installed)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddModel(Model("llama3.2", 2<<30))
+	if err := srv.LoadModel("llama3.2:latest", 0, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Delete(context.Background(), &api.DeleteRequest{Model: "llama3.2"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.Installed()) != 0 || len(srv.Running()) != 0 {
+		t.Fatalf("model still installed or running")
+	}
+
+	if err := client.Delete(context.Background(), &api.DeleteRequest{Model: "llama3.2"}); err == nil {
+		t.Fatal("deleting a missing model succeeded")
+	}
+}
+
+func TestShow(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddModel(Model("llama3.2", 2<<30))
+	srv.AddModel(Model("qwen2.5:7b", 4<<30))
+	srv.SetShow("qwen2.5:7b", api.ShowResponse{System: "Be brief.", Template: "{{ .System }} {{ .Prompt }}"})
+
+	show, err := client.Show(context.Background(), &api.ShowRequest{Model: "llama3.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if show.Template != "{{ .Prompt }}" || show.Details.Format != "gguf" {
+		t.Fatalf("derived show = %+v", show)
+	}
+
+	show, err = client.Show(context.Background(), &api.ShowRequest{Model: "qwen2.5:7b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if show.System != "Be brief." {
+		t.Fatalf("show override ignored: %+v", show)
+	}
+
+	if _, err := client.Show(context.Background(), &api.ShowRequest{Model: "missing"}); err == nil {
+		t.Fatal("showing a missing model succeeded")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddModel(Model("llama3.2", 2<<30))
+	srv.SetResponse("llama3.2", "The sky is blue.")
+
+	var response strings.Builder
+	var final api.GenerateResponse
+	err := client.Generate(context.Background(), &api.GenerateRequest{Model: "llama3.2", Prompt: "Why?"}, func(resp api.GenerateResponse) error {
+		response.WriteString(resp.Response)
+		final = resp
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.String() != "The sky is blue." {
+		t.Fatalf("response = %q", response.String())
+	}
+	if !final.Done || final.EvalCount != 4 {
+		t.Fatalf("final = %+v", final)
+	}
+	if len(srv.Running()) != 1 {
+		t.Fatal("generating did not load the model")
+	}
+
+	// an empty prompt with keep_alive 0 unloads the model
+	err = client.Generate(context.Background(), &api.GenerateRequest{Model: "llama3.2", KeepAlive: &api.Duration{}}, func(resp api.GenerateResponse) error {
+		final = resp
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if final.DoneReason != "unload" || len(srv.Running()) != 0 {
+		t.Fatalf("model not unloaded: %+v", final)
+	}
+}
+
+func TestChat(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddModel(Model("llama3.2", 2<<30))
+	srv.SetResponse("llama3.2:latest", "Hi there!")
+
+	var reply strings.Builder
+	req := &api.ChatRequest{
+		Model:    "llama3.2",
+		Messages: []api.Message{{Role: "user", Content: "Hello"}},
+	}
+	err := client.Chat(context.Background(), req, func(resp api.ChatResponse) error {
+		reply.WriteString(resp.Message.Content)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.String() != "Hi there!" {
+		t.Fatalf("reply = %q", reply.String())
+	}
+
+	req.Tools = api.Tools{{Type: "function", Function: api.ToolFunction{Name: "get_weather"}}}
+	req.Tools[0].Function.Parameters.Required = []string{"city"}
+
+	var calls []api.ToolCall
+	err = client.Chat(context.Background(), req, func(resp api.ChatResponse) error {
+		calls = append(calls, resp.Message.ToolCalls...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || calls[0].Function.Name != "get_weather" || calls[0].Function.Arguments["city"] != "test" {
+		t.Fatalf("tool calls = %+v", calls)
+	}
+}
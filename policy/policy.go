@@ -0,0 +1,258 @@
+// Package policy restricts which models can be pulled, e.g. onto a shared
+// server. The policy is read from policy.json in the ollamanager config
+// directory; without one every model is allowed.
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gaurav-gosain/ollamanager/config"
+)
+
+// Policy lists the rules a model must pass before it is pulled.
+type Policy struct {
+	// Allow and Deny are `name:tag` globs, e.g. "llama3*" or "qwen2.5:*-q4_K_M".
+	// A glob without a tag matches every tag. When Allow is set only the
+	// models it matches can be pulled, Deny always wins.
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+
+	// MaxParameters caps the parameter count, e.g. "14B".
+	MaxParameters string `json:"max_parameters,omitempty"`
+	// MaxDownloadSize caps the size of the download, e.g. "20GB".
+	MaxDownloadSize string `json:"max_download_size,omitempty"`
+	// Quantizations lists the allowed quantization levels, e.g. "Q4_K_M".
+	Quantizations []string `json:"quantizations,omitempty"`
+}
+
+// Model is what the size and quantization rules are checked against, as
+// published by the registry.
+type Model struct {
+	Name          string
+	ParameterSize string
+	Quantization  string
+	Size          int64
+}
+
+func Path() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "policy.json"), nil
+}
+
+// Load reads the policy file. A missing file is an empty policy that allows
+// everything.
+func Load() (Policy, error) {
+	var p Policy
+
+	path, err := Path()
+	if err != nil {
+		return p, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, errors.New("invalid policy " + path + ": " + err.Error())
+	}
+
+	if _, err := p.maxParameters(); err != nil {
+		return p, errors.New("invalid policy " + path + ": " + err.Error())
+	}
+	if _, err := p.maxDownloadSize(); err != nil {
+		return p, errors.New("invalid policy " + path + ": " + err.Error())
+	}
+
+	return p, nil
+}
+
+// HasLimits reports whether the policy has rules that need the model details
+// from the registry, not just its name.
+func (p Policy) HasLimits() bool {
+	return p.MaxParameters != "" || p.MaxDownloadSize != "" || len(p.Quantizations) > 0
+}
+
+// CheckName checks the model name against the allow and deny lists.
+func (p Policy) CheckName(name string) error {
+	name = withTag(name)
+
+	for _, pattern := range p.Deny {
+		if match(pattern, name) {
+			return fmt.Errorf("%s is denied by policy (%s)", name, pattern)
+		}
+	}
+
+	if len(p.Allow) > 0 && !slices.ContainsFunc(p.Allow, func(pattern string) bool { return match(pattern, name) }) {
+		return fmt.Errorf("%s is not in the policy allowlist", name)
+	}
+
+	return nil
+}
+
+// CheckModelName checks a model whose tag is not picked yet, e.g. an entry of
+// the Install tab. It fails only if no tag of the model could be allowed.
+func (p Policy) CheckModelName(name string) error {
+	for _, pattern := range p.Deny {
+		namePattern, tagPattern := splitPattern(pattern)
+		if tagPattern == "*" && globMatch(namePattern, name) {
+			return fmt.Errorf("denied by policy (%s)", pattern)
+		}
+	}
+
+	if len(p.Allow) > 0 && !slices.ContainsFunc(p.Allow, func(pattern string) bool {
+		namePattern, _ := splitPattern(pattern)
+		return globMatch(namePattern, name)
+	}) {
+		return errors.New("not in the policy allowlist")
+	}
+
+	return nil
+}
+
+// Check checks the name, size and quantization of the model.
+func (p Policy) Check(m Model) error {
+	if err := p.CheckName(m.Name); err != nil {
+		return err
+	}
+
+	if maxParams, _ := p.maxParameters(); maxParams > 0 {
+		params, err := parseParameters(m.ParameterSize)
+		if err != nil {
+			return fmt.Errorf("%s has an unknown parameter count, the policy caps it at %s", m.Name, p.MaxParameters)
+		}
+		if params > maxParams {
+			return fmt.Errorf("%s has %s parameters, the policy allows up to %s", m.Name, m.ParameterSize, p.MaxParameters)
+		}
+	}
+
+	if maxSize, _ := p.maxDownloadSize(); maxSize > 0 && uint64(m.Size) > maxSize {
+		return fmt.Errorf(
+			"%s is a %s download, the policy allows up to %s",
+			m.Name, humanize.Bytes(uint64(m.Size)), p.MaxDownloadSize,
+		)
+	}
+
+	if len(p.Quantizations) > 0 && !slices.ContainsFunc(p.Quantizations, func(q string) bool {
+		return strings.EqualFold(q, m.Quantization)
+	}) {
+		return fmt.Errorf(
+			"%s is quantized as %s, the policy allows %s",
+			m.Name, m.Quantization, strings.Join(p.Quantizations, ", "),
+		)
+	}
+
+	return nil
+}
+
+func (p Policy) maxParameters() (float64, error) {
+	if p.MaxParameters == "" {
+		return 0, nil
+	}
+
+	return parseParameters(p.MaxParameters)
+}
+
+func (p Policy) maxDownloadSize() (uint64, error) {
+	if p.MaxDownloadSize == "" {
+		return 0, nil
+	}
+
+	return humanize.ParseBytes(p.MaxDownloadSize)
+}
+
+// parseParameters parses parameter counts like "8.0B", "70b" or "137M".
+func parseParameters(s string) (float64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(s, "T"):
+		multiplier = 1e12
+	case strings.HasSuffix(s, "B"):
+		multiplier = 1e9
+	case strings.HasSuffix(s, "M"):
+		multiplier = 1e6
+	case strings.HasSuffix(s, "K"):
+		multiplier = 1e3
+	}
+	if multiplier != 1 {
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid parameter count %q", s)
+	}
+
+	return n * multiplier, nil
+}
+
+func match(pattern, name string) bool {
+	namePattern, tagPattern := splitPattern(pattern)
+	modelName, tag, _ := cutTag(name)
+
+	return globMatch(namePattern, modelName) && globMatch(tagPattern, tag)
+}
+
+// splitPattern splits a glob into its name and tag parts, the tag defaulting
+// to any tag.
+func splitPattern(pattern string) (string, string) {
+	namePattern, tagPattern, ok := cutTag(pattern)
+	if !ok || tagPattern == "" {
+		tagPattern = "*"
+	}
+
+	return namePattern, tagPattern
+}
+
+// globMatch matches s against a path.Match glob, case insensitively. Unlike
+// path.Match, * also matches "/" so that e.g. "*" or "hf.co/*" cover names
+// with a namespace or host.
+func globMatch(pattern, s string) bool {
+	ok, err := path.Match(unslash(strings.ToLower(pattern)), unslash(strings.ToLower(s)))
+	return err == nil && ok
+}
+
+// unslash swaps "/" for a byte model names never contain, which path.Match
+// treats like any other.
+func unslash(s string) string {
+	return strings.ReplaceAll(s, "/", "\x00")
+}
+
+func withTag(name string) string {
+	if _, _, ok := cutTag(name); !ok {
+		return name + ":latest"
+	}
+
+	return name
+}
+
+// cutTag splits the tag off a model name, leaving the port of a registry
+// host alone.
+func cutTag(name string) (string, string, bool) {
+	i := strings.LastIndex(name, ":")
+	if i < 0 || i < strings.LastIndex(name, "/") {
+		return name, "", false
+	}
+
+	return name[:i], name[i+1:], true
+}
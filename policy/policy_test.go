@@ -0,0 +1,96 @@
+package policy
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"llama3*", "llama3.2", true},
+		{"LLAMA3*", "llama3.2", true},
+		{"llama3*", "qwen2.5", false},
+		{"*", "llama3.2", true},
+		{"*", "hf.co/bartowski/llama-3.2-1b-gguf", true},
+		{"hf.co/*", "hf.co/bartowski/llama-3.2-1b-gguf", true},
+		{"hf.co/*", "registry.example.com/team/model", false},
+		{"*/llama3*", "myuser/llama3.2", true},
+		{"*/llama3*", "llama3.2", false},
+		{"*-fp16", "8b-instruct-fp16", true},
+		{"llama3.?", "llama3.2", true},
+		{"llama3.?", "llama3.21", false},
+		{"[", "[", false},
+	}
+
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"llama3*", "llama3.2:latest", true},
+		{"llama3*:1b", "llama3.2:1b", true},
+		{"llama3*:1b", "llama3.2:3b", false},
+		{"*:*-fp16", "llama3.1:8b-instruct-fp16", true},
+		{"*:*-fp16", "llama3.1:8b", false},
+		{"localhost:5000/*", "localhost:5000/team/model:latest", true},
+	}
+
+	for _, tt := range tests {
+		if got := match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCutTag(t *testing.T) {
+	tests := []struct {
+		in, name, tag string
+		ok            bool
+	}{
+		{"llama3.2", "llama3.2", "", false},
+		{"llama3.2:1b", "llama3.2", "1b", true},
+		{"llama3.2:", "llama3.2", "", true},
+		{"localhost:5000/team/model", "localhost:5000/team/model", "", false},
+		{"localhost:5000/team/model:v1", "localhost:5000/team/model", "v1", true},
+		{"hf.co/user/repo:Q4_K_M", "hf.co/user/repo", "Q4_K_M", true},
+	}
+
+	for _, tt := range tests {
+		name, tag, ok := cutTag(tt.in)
+		if name != tt.name || tag != tt.tag || ok != tt.ok {
+			t.Errorf("cutTag(%q) = %q, %q, %v, want %q, %q, %v", tt.in, name, tag, ok, tt.name, tt.tag, tt.ok)
+		}
+	}
+}
+
+func TestParseParameters(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"8.0B", 8e9, true},
+		{"70b", 70e9, true},
+		{" 137M ", 137e6, true},
+		{"1.5T", 1.5e12, true},
+		{"500K", 500e3, true},
+		{"1234", 1234, true},
+		{"", 0, false},
+		{"B", 0, false},
+		{"lots", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parseParameters(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseParameters(%q) = %v, %v, want %v (ok %v)", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
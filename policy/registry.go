@@ -0,0 +1,117 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gaurav-gosain/ollamanager/store"
+)
+
+// RegistryURL is the registry models without a host are pulled from. It can
+// be pointed at a fake registry (see ollamatest).
+var RegistryURL = "https://registry.ollama.ai"
+
+// modelConfig is the part of the config blob of a model the policy needs.
+type modelConfig struct {
+	ModelType string `json:"model_type"`
+	FileType  string `json:"file_type"`
+}
+
+// Remote fetches the manifest and config of the model from its registry,
+// without pulling it.
+func Remote(name string) (Model, error) {
+	m := Model{Name: withTag(name)}
+
+	base, repository, _ := registryPath(m.Name)
+
+	manifest, err := RemoteManifest(m.Name)
+	if err != nil {
+		return m, err
+	}
+
+	for _, layer := range manifest.Blobs() {
+		m.Size += layer.Size
+	}
+
+	var config modelConfig
+	if manifest.Config.Digest != "" {
+		err = getJSON(base+"/v2/"+repository+"/blobs/"+manifest.Config.Digest, &config)
+		if err != nil {
+			return m, err
+		}
+	}
+
+	m.ParameterSize = config.ModelType
+	m.Quantization = config.FileType
+
+	return m, nil
+}
+
+// RemoteManifest fetches the manifest of the model from its registry.
+func RemoteManifest(name string) (store.Manifest, error) {
+	base, repository, tag := registryPath(withTag(name))
+
+	var manifest store.Manifest
+	err := getJSON(base+"/v2/"+repository+"/manifests/"+tag, &manifest)
+
+	return manifest, err
+}
+
+// Enforce checks the model name and, when the policy has size or
+// quantization rules, the details published by its registry. A model whose
+// details cannot be fetched is refused.
+func (p Policy) Enforce(name string) error {
+	if err := p.CheckName(name); err != nil {
+		return err
+	}
+
+	if !p.HasLimits() {
+		return nil
+	}
+
+	m, err := Remote(name)
+	if err != nil {
+		return fmt.Errorf("could not check %s against the policy: %s", name, err.Error())
+	}
+
+	return p.Check(m)
+}
+
+// registryPath returns the registry URL, repository and tag of a model name.
+func registryPath(name string) (base, repository, tag string) {
+	name, tag, _ = cutTag(name)
+
+	base = RegistryURL
+	parts := strings.Split(name, "/")
+	switch len(parts) {
+	case 1:
+		return base, "library/" + name, tag
+	case 3:
+		base = "https://" + parts[0]
+		parts = parts[1:]
+	}
+
+	return base, strings.Join(parts, "/"), tag
+}
+
+func getJSON(url string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.docker.distribution.manifest.v2+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry returned %s for %s", resp.Status, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,46 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gaurav-gosain/ollamanager/genopts"
+)
+
+// OptionsText formats the options as "key value" pairs sorted by key.
+func (s Session) OptionsText() string {
+	return genopts.Options(s.Options).String()
+}
+
+// Markdown renders the session with its model, options and messages.
+func (s Session) Markdown() string {
+	var md strings.Builder
+
+	fmt.Fprintf(&md, "# %s\n\n", s.Name)
+	fmt.Fprintf(&md, "- Model: `%s`\n", s.Model)
+	fmt.Fprintf(&md, "- Created: %s\n", s.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&md, "- Updated: %s\n", s.UpdatedAt.Format("2006-01-02 15:04"))
+	if len(s.Options) > 0 {
+		fmt.Fprintf(&md, "- Options: %s\n", s.OptionsText())
+	}
+
+	if s.System != "" {
+		fmt.Fprintf(&md, "\n## System\n\n%s\n", strings.TrimSpace(s.System))
+	}
+
+	for _, message := range s.Messages {
+		role := message.Role
+		if role != "" {
+			role = strings.ToUpper(role[:1]) + role[1:]
+		}
+		fmt.Fprintf(&md, "\n## %s\n\n%s\n", role, strings.TrimSpace(message.Content))
+	}
+
+	return md.String()
+}
+
+// JSON returns the session as saved.
+func (s Session) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
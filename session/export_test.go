@@ -0,0 +1,60 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+func testSession() Session {
+	return Session{
+		ID:      "20241120-120000-abcdef",
+		Name:    "Haiku",
+		Model:   "llama3.2:latest",
+		System:  "You are a poet.\n",
+		Options: map[string]any{"temperature": 0.9, "stop": []string{"</s>"}},
+		Messages: []api.Message{
+			{Role: "user", Content: "Write a haiku about the sea."},
+			{Role: "assistant", Content: "Waves fold into foam\nthe tide keeps its own counsel\nsalt on the old stones\n"},
+		},
+		CreatedAt: time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 11, 20, 12, 5, 0, 0, time.UTC),
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	want := "# Haiku\n\n" +
+		"- Model: `llama3.2:latest`\n" +
+		"- Created: 2024-11-20 12:00\n" +
+		"- Updated: 2024-11-20 12:05\n" +
+		"- Options: stop [\"</s>\"], temperature 0.9\n" +
+		"\n## System\n\nYou are a poet.\n" +
+		"\n## User\n\nWrite a haiku about the sea.\n" +
+		"\n## Assistant\n\nWaves fold into foam\nthe tide keeps its own counsel\nsalt on the old stones\n"
+
+	if got := testSession().Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	s := testSession()
+
+	data, err := s.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Session
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.ID != s.ID || got.Name != s.Name || got.System != s.System ||
+		len(got.Messages) != 2 || got.Messages[1].Content != s.Messages[1].Content ||
+		!got.CreatedAt.Equal(s.CreatedAt) || got.Options["temperature"] != 0.9 {
+		t.Errorf("round trip = %+v, want %+v", got, s)
+	}
+}
@@ -0,0 +1,202 @@
+// Package session persists chat conversations per model in the ollamanager
+// data dir, so they can be resumed and exported.
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/ollama/ollama/api"
+)
+
+// Session is a saved conversation with a model, along with everything needed
+// to reproduce it.
+type Session struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Model string `json:"model"`
+	// System is the system prompt sent before the messages.
+	System string `json:"system,omitempty"`
+	// Options are the Ollama request options, e.g. "temperature" or "num_ctx".
+	Options   map[string]any `json:"options,omitempty"`
+	Messages  []api.Message  `json:"messages"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// New returns an unsaved session with the model.
+func New(model, name string) Session {
+	now := time.Now()
+
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+
+	return Session{
+		ID:        now.Format("20060102-150405") + "-" + hex.EncodeToString(suffix),
+		Name:      name,
+		Model:     model,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// ChatMessages returns the messages to send, the system prompt first.
+func (s Session) ChatMessages() []api.Message {
+	if s.System == "" {
+		return s.Messages
+	}
+
+	return append([]api.Message{{Role: "system", Content: s.System}}, s.Messages...)
+}
+
+// Dir returns the directory sessions are saved in.
+func Dir() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "sessions"), nil
+}
+
+// path returns where the session is saved, in a directory per model.
+func (s Session) path(dir string) string {
+	model := strings.NewReplacer("/", "_", ":", "_").Replace(s.Model)
+	return filepath.Join(dir, model, s.ID+".json")
+}
+
+// Save writes the session, replacing its previous version.
+func Save(s *Session) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	s.UpdatedAt = time.Now()
+
+	path := s.path(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := s.JSON()
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so a crash never loses the session
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// List returns every saved session, most recently updated first. Unreadable
+// files are skipped.
+func List() ([]Session, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, path := range paths {
+		s, err := read(path)
+		if err != nil {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+
+	slices.SortFunc(sessions, func(a, b Session) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+
+	return sessions, nil
+}
+
+// Load reads the session with the given ID.
+func Load(id string) (Session, error) {
+	path, err := find(id)
+	if err != nil {
+		return Session{}, err
+	}
+
+	return read(path)
+}
+
+// Rename changes the name of the session.
+func Rename(id, name string) error {
+	s, err := Load(id)
+	if err != nil {
+		return err
+	}
+
+	s.Name = name
+
+	return Save(&s)
+}
+
+// Delete removes the session.
+func Delete(id string) error {
+	path, err := find(id)
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+func find(id string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	// ids are globbed below, so glob patterns are rejected along with paths
+	if id == "" || strings.ContainsAny(id, `/\*?[`) {
+		return "", fmt.Errorf("invalid session %q", id)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*", id+".json"))
+	if err != nil {
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("session %s: %w", id, fs.ErrNotExist)
+	}
+
+	return paths[0], nil
+}
+
+func read(path string) (Session, error) {
+	var s Session
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, errors.New("invalid session " + path + ": " + err.Error())
+	}
+
+	return s, nil
+}
@@ -0,0 +1,157 @@
+package session
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+func setDataDir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("OLLAMANAGER_DATA_DIR", dir)
+
+	return filepath.Join(dir, "sessions")
+}
+
+func TestSaveWritesPerModel(t *testing.T) {
+	dir := setDataDir(t)
+
+	s := New("hf.co/user/model:Q4_K_M", "quantized")
+	if err := Save(&s); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "hf.co_user_model_Q4_K_M", s.ID+".json")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("session not saved in its model directory: %s", err)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*", "*.tmp")); len(tmp) > 0 {
+		t.Fatalf("temporary files left behind: %v", tmp)
+	}
+}
+
+func TestSaveLoadList(t *testing.T) {
+	setDataDir(t)
+
+	first := New("llama3.2:latest", "first")
+	first.System = "You are terse."
+	first.Options = map[string]any{"temperature": 0.2}
+	first.Messages = []api.Message{{Role: "user", Content: "Hi"}, {Role: "assistant", Content: "Hello."}}
+	second := New("qwen2.5:7b", "second")
+
+	for _, s := range []*Session{&first, &second} {
+		if err := Save(s); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	loaded, err := Load(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Name != "first" || loaded.System != first.System || len(loaded.Messages) != 2 || loaded.Options["temperature"] != 0.2 {
+		t.Fatalf("loaded = %+v", loaded)
+	}
+
+	assertOrder(t, second.ID, first.ID)
+
+	// saving again moves the session to the front
+	if err := Save(&first); err != nil {
+		t.Fatal(err)
+	}
+	assertOrder(t, first.ID, second.ID)
+}
+
+func assertOrder(t *testing.T, ids ...string) {
+	t.Helper()
+
+	sessions, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, s := range sessions {
+		got = append(got, s.ID)
+	}
+	if strings.Join(got, " ") != strings.Join(ids, " ") {
+		t.Fatalf("List() = %v, want %v", got, ids)
+	}
+}
+
+func TestListSkipsUnreadable(t *testing.T) {
+	dir := setDataDir(t)
+
+	s := New("llama3.2:latest", "chat")
+	if err := Save(&s); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "llama3.2_latest", "broken.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != s.ID {
+		t.Fatalf("List() = %+v", sessions)
+	}
+}
+
+func TestRenameAndDelete(t *testing.T) {
+	setDataDir(t)
+
+	s := New("llama3.2:latest", "chat")
+	if err := Save(&s); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Rename(s.ID, "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Name != "renamed" {
+		t.Fatalf("name = %q, want renamed", loaded.Name)
+	}
+
+	if err := Delete(s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(s.ID); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Load() after Delete = %v, want fs.ErrNotExist", err)
+	}
+	if err := Delete(s.ID); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("second Delete = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestFindRejectsPaths(t *testing.T) {
+	setDataDir(t)
+
+	s := New("llama3.2:latest", "chat")
+	if err := Save(&s); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"", "../config", `..\config`, "llama3.2_latest/" + s.ID, "*", s.ID[:4] + "?*", "[0-9]*"} {
+		if _, err := Load(id); err == nil {
+			t.Errorf("Load(%q) succeeded", id)
+		}
+		if err := Delete(id); err == nil {
+			t.Errorf("Delete(%q) succeeded", id)
+		}
+	}
+}
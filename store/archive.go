@@ -0,0 +1,339 @@
+package store
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ollama/ollama/api"
+)
+
+// IndexName is the name of the checksum index, the first entry of every
+// archive.
+const IndexName = "index.json"
+
+// zstdMagic starts every zstd frame.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// Index lists the models in an archive and the checksum of every file.
+type Index struct {
+	Models []string    `json:"models"`
+	Files  []IndexFile `json:"files"`
+}
+
+// IndexFile is a file of an archive, with its path relative to the models
+// directory.
+type IndexFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Export writes the manifests and blobs of the models to w as a tar archive,
+// zstd-compressed if compress is set. The index comes first, then the blobs
+// and then the manifests, so that an interrupted import never leaves a
+// manifest without its blobs. Blobs are re-hashed while they are written and
+// the export fails if one does not match its digest. Names of the same model
+// (e.g. "llama3.2" and "llama3.2:latest") are exported once.
+func (s Store) Export(w io.Writer, names []string, compress bool, fn func(api.ProgressResponse) error) error {
+	var index Index
+	manifests := map[string][]byte{}
+	var blobs []Layer
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		manifestPath := path.Join("manifests", fullName(name))
+		if _, ok := manifests[manifestPath]; ok {
+			continue
+		}
+		index.Models = append(index.Models, name)
+
+		data, err := os.ReadFile(s.ManifestPath(name))
+		if err != nil {
+			return fmt.Errorf("failed to read manifest of %s: %s", name, err.Error())
+		}
+
+		var m Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return fmt.Errorf("failed to parse manifest of %s: %s", name, err.Error())
+		}
+
+		for _, layer := range m.Blobs() {
+			if seen[layer.Digest] {
+				continue
+			}
+			seen[layer.Digest] = true
+			blobs = append(blobs, layer)
+
+			index.Files = append(index.Files, IndexFile{
+				Path:   blobArchivePath(layer.Digest),
+				SHA256: strings.TrimPrefix(layer.Digest, "sha256:"),
+				Size:   layer.Size,
+			})
+		}
+
+		manifests[manifestPath] = data
+		sum := sha256.Sum256(data)
+		index.Files = append(index.Files, IndexFile{
+			Path:   manifestPath,
+			SHA256: hex.EncodeToString(sum[:]),
+			Size:   int64(len(data)),
+		})
+	}
+
+	var zw *zstd.Encoder
+	if compress {
+		var err error
+		if zw, err = zstd.NewWriter(w); err != nil {
+			return err
+		}
+		defer zw.Close()
+		w = zw
+	}
+
+	tw := tar.NewWriter(w)
+
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, IndexName, bytes.NewReader(indexData), int64(len(indexData))); err != nil {
+		return err
+	}
+
+	for _, layer := range blobs {
+		if err := s.exportBlob(tw, layer, fn); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range index.Files {
+		data, ok := manifests[file.Path]
+		if !ok {
+			continue
+		}
+		if err := writeTarFile(tw, file.Path, bytes.NewReader(data), file.Size); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	if zw != nil {
+		return zw.Close()
+	}
+
+	return nil
+}
+
+func (s Store) exportBlob(tw *tar.Writer, layer Layer, fn func(api.ProgressResponse) error) error {
+	f, err := os.Open(s.BlobPath(layer.Digest))
+	if err != nil {
+		return fmt.Errorf("failed to open blob %s: %s", layer.Digest, err.Error())
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() != layer.Size {
+		return fmt.Errorf("blob %s is %d bytes, expected %d", layer.Digest, info.Size(), layer.Size)
+	}
+
+	h := sha256.New()
+	r := NewProgressReader(io.TeeReader(f, h), "exporting "+shortDigest(layer.Digest), layer.Digest, layer.Size, fn)
+
+	if err := writeTarFile(tw, blobArchivePath(layer.Digest), r, layer.Size); err != nil {
+		return err
+	}
+
+	return checkSum(h, strings.TrimPrefix(layer.Digest, "sha256:"), layer.Digest)
+}
+
+// Import verifies the archive read from r against its index and unpacks it
+// into the store, compressed or not. Blobs the store already has intact are
+// kept.
+// It returns the names of the imported models.
+func (s Store) Import(r io.Reader, fn func(api.ProgressResponse) error) ([]string, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(zstdMagic)); bytes.Equal(magic, zstdMagic) {
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+
+	tr := tar.NewReader(r)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %s", err.Error())
+	}
+	if hdr.Name != IndexName {
+		return nil, fmt.Errorf("not a model archive, %s is missing", IndexName)
+	}
+
+	var index Index
+	if err := json.NewDecoder(tr).Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", IndexName, err.Error())
+	}
+
+	files := map[string]IndexFile{}
+	for _, file := range index.Files {
+		files[file.Path] = file
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %s", err.Error())
+		}
+
+		file, ok := files[hdr.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not listed in %s", hdr.Name, IndexName)
+		}
+		delete(files, hdr.Name)
+
+		if err := s.importFile(tr, file, fn); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(files) > 0 {
+		return nil, fmt.Errorf("archive is incomplete, %d files are missing", len(files))
+	}
+
+	return index.Models, nil
+}
+
+// importFile writes a file of the archive next to its destination, checks
+// its checksum and moves it into place.
+func (s Store) importFile(r io.Reader, file IndexFile, fn func(api.ProgressResponse) error) error {
+	if !validArchivePath(file.Path) {
+		return fmt.Errorf("invalid path %q in archive", file.Path)
+	}
+
+	dest := filepath.Join(s.Dir, filepath.FromSlash(file.Path))
+
+	if strings.HasPrefix(file.Path, "blobs/") {
+		// blobs are stored by digest, a blob under another name would
+		// pass its checksum and still be missing for its manifest
+		if file.Path != blobArchivePath("sha256:"+file.SHA256) {
+			return fmt.Errorf("blob %s does not match its checksum sha256:%s", file.Path, file.SHA256)
+		}
+
+		if info, err := os.Stat(dest); err == nil && info.Size() == file.Size {
+			status, err := s.verifyBlob(Layer{Digest: "sha256:" + file.SHA256, Size: file.Size}, fn)
+			if err != nil {
+				return err
+			}
+			if status == OK {
+				_ = fn(api.ProgressResponse{Status: "using existing blob " + shortDigest(file.SHA256)})
+				_, err := io.Copy(io.Discard, r)
+				return err
+			}
+			// a corrupted blob is replaced by the archived one
+		}
+
+		r = NewProgressReader(r, "importing "+shortDigest(file.SHA256), "sha256:"+file.SHA256, file.Size, fn)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), filepath.Base(dest)+"-partial-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := checkSum(h, file.SHA256, file.Path); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dest)
+}
+
+func writeTarFile(tw *tar.Writer, name string, r io.Reader, size int64) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    size,
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, r)
+	return err
+}
+
+func checkSum(h hash.Hash, want, name string) error {
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got sha256:%s", name, got)
+	}
+
+	return nil
+}
+
+func blobArchivePath(digest string) string {
+	return "blobs/" + strings.Replace(digest, ":", "-", 1)
+}
+
+// validArchivePath rejects paths that would be unpacked outside of the
+// manifests and blobs directories.
+func validArchivePath(p string) bool {
+	if p != path.Clean(p) || path.IsAbs(p) || strings.Contains(p, "..") {
+		return false
+	}
+
+	return strings.HasPrefix(p, "blobs/") || strings.HasPrefix(p, "manifests/")
+}
+
+func shortDigest(digest string) string {
+	digest = strings.TrimPrefix(digest, "sha256:")
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+
+	return digest
+}
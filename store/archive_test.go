@@ -0,0 +1,165 @@
+package store
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+)
+
+func noProgress(api.ProgressResponse) error { return nil }
+
+// writeModel stores a model with a config and a weights blob and returns the
+// manifest.
+func writeModel(t *testing.T, s Store, name string, weights []byte) Manifest {
+	t.Helper()
+
+	writeBlob := func(data []byte) Layer {
+		digest := fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+		if err := os.MkdirAll(filepath.Dir(s.BlobPath(digest)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(s.BlobPath(digest), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return Layer{Digest: digest, Size: int64(len(data))}
+	}
+
+	m := Manifest{
+		SchemaVersion: 2,
+		Config:        writeBlob([]byte(`{"model_format":"gguf"}`)),
+		Layers:        []Layer{writeBlob(weights)},
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.ManifestPath(name)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.ManifestPath(name), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+// archiveEntries lists the file names of an uncompressed archive.
+func archiveEntries(t *testing.T, archive []byte) []string {
+	t.Helper()
+
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+
+	return names
+}
+
+func TestExportDedupesNames(t *testing.T) {
+	s := Store{Dir: t.TempDir()}
+	writeModel(t, s, "llama3.2", []byte("weights"))
+
+	names := []string{"llama3.2", "llama3.2:latest", "llama3.2"}
+	var archive bytes.Buffer
+	if err := s.Export(&archive, names, false, noProgress); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(names, []string{"llama3.2", "llama3.2:latest", "llama3.2"}) {
+		t.Fatalf("names modified: %v", names)
+	}
+
+	var manifests int
+	for _, name := range archiveEntries(t, archive.Bytes()) {
+		if strings.HasPrefix(name, "manifests/") {
+			manifests++
+		}
+	}
+	if manifests != 1 {
+		t.Fatalf("%d manifests exported, want 1", manifests)
+	}
+
+	imported, err := Store{Dir: t.TempDir()}.Import(&archive, noProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(imported, []string{"llama3.2"}) {
+		t.Fatalf("imported %v", imported)
+	}
+}
+
+func TestImportReplacesCorruptBlob(t *testing.T) {
+	source := Store{Dir: t.TempDir()}
+	m := writeModel(t, source, "llama3.2", []byte("weights"))
+
+	var archive bytes.Buffer
+	if err := source.Export(&archive, []string{"llama3.2"}, true, noProgress); err != nil {
+		t.Fatal(err)
+	}
+
+	// same size, different content
+	dest := Store{Dir: t.TempDir()}
+	weights := dest.BlobPath(m.Layers[0].Digest)
+	if err := os.MkdirAll(filepath.Dir(weights), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(weights, []byte("WEIGHTS"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dest.Import(&archive, noProgress); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile(weights); string(data) != "weights" {
+		t.Fatalf("blob = %q, want the archived one", data)
+	}
+}
+
+func TestImportRejectsMisplacedBlob(t *testing.T) {
+	data := []byte("weights")
+	sum := fmt.Sprintf("%x", sha256.Sum256(data))
+	other := strings.Repeat("0", 64)
+
+	index, err := json.Marshal(Index{Files: []IndexFile{
+		{Path: "blobs/sha256-" + other, SHA256: sum, Size: int64(len(data))},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	if err := writeTarFile(tw, IndexName, bytes.NewReader(index), int64(len(index))); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTarFile(tw, "blobs/sha256-"+other, bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := Store{Dir: t.TempDir()}
+	if _, err := s.Import(&archive, noProgress); err == nil {
+		t.Fatal("imported a blob stored under another digest")
+	}
+	if _, err := os.Stat(s.BlobPath("sha256:" + other)); err == nil {
+		t.Fatal("misplaced blob was written")
+	}
+}
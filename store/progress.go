@@ -0,0 +1,59 @@
+package store
+
+import (
+	"io"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// progressInterval is how often ProgressReader reports at most, besides
+// reporting the end of the file.
+const progressInterval = 100 * time.Millisecond
+
+// ProgressReader reports how much of a file has been read to fn, at most
+// every progressInterval so that multi-GB blobs don't flood the progress
+// view.
+type ProgressReader struct {
+	io.Reader
+	status    string
+	digest    string
+	total     int64
+	completed int64
+	fn        func(api.ProgressResponse) error
+	// reported is when progress was last reported.
+	reported time.Time
+}
+
+// NewProgressReader returns a reader reporting the progress of reading total
+// bytes from r with the status and digest.
+func NewProgressReader(r io.Reader, status, digest string, total int64, fn func(api.ProgressResponse) error) *ProgressReader {
+	return &ProgressReader{
+		Reader: r,
+		status: status,
+		digest: digest,
+		total:  total,
+		fn:     fn,
+	}
+}
+
+func (r *ProgressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.completed += int64(n)
+
+	done := err != nil || r.completed >= r.total
+	if !done && time.Since(r.reported) < progressInterval {
+		return n, err
+	}
+	r.reported = time.Now()
+
+	if fnErr := r.fn(api.ProgressResponse{
+		Status:    r.status,
+		Digest:    r.digest,
+		Total:     r.total,
+		Completed: r.completed,
+	}); fnErr != nil {
+		return n, fnErr
+	}
+	return n, err
+}
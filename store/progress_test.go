@@ -0,0 +1,46 @@
+package store
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+)
+
+func TestProgressReaderThrottles(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 1<<20)
+
+	var reports []api.ProgressResponse
+	r := NewProgressReader(&chunkReader{bytes.NewReader(data)}, "reading", "", int64(len(data)), func(p api.ProgressResponse) error {
+		reports = append(reports, p)
+		return nil
+	})
+
+	var b strings.Builder
+	buf := make([]byte, 1024)
+	for {
+		n, err := r.Read(buf)
+		b.Write(buf[:n])
+		if err != nil {
+			break
+		}
+	}
+
+	if b.Len() != len(data) {
+		t.Fatalf("read %d bytes, want %d", b.Len(), len(data))
+	}
+	if len(reports) > 100 {
+		t.Fatalf("%d progress reports for %d reads", len(reports), len(data)/1024)
+	}
+	if last := reports[len(reports)-1]; last.Completed != int64(len(data)) {
+		t.Fatalf("last report = %+v", last)
+	}
+}
+
+// chunkReader limits every read to a kilobyte, like a slow network.
+type chunkReader struct{ r *bytes.Reader }
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	return r.r.Read(p[:min(len(p), 1024)])
+}
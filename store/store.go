@@ -0,0 +1,266 @@
+// Package store reads the manifests and blobs of a local Ollama models
+// directory, for the operations the Ollama API does not cover.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ollama/ollama/envconfig"
+)
+
+const (
+	defaultHost      = "registry.ollama.ai"
+	defaultNamespace = "library"
+	defaultTag       = "latest"
+)
+
+// Layer is a blob referenced by a manifest.
+type Layer struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// Manifest is the OCI-style manifest Ollama stores for every model.
+type Manifest struct {
+	SchemaVersion int     `json:"schemaVersion"`
+	MediaType     string  `json:"mediaType"`
+	Config        Layer   `json:"config"`
+	Layers        []Layer `json:"layers"`
+}
+
+// Blobs returns the config and layers of the manifest.
+func (m Manifest) Blobs() []Layer {
+	blobs := slices.Clone(m.Layers)
+	if m.Config.Digest != "" {
+		blobs = append([]Layer{m.Config}, blobs...)
+	}
+
+	return blobs
+}
+
+// Store is a models directory laid out like Ollama's, with manifests under
+// manifests/host/namespace/model/tag and blobs under blobs/sha256-<hex>.
+type Store struct {
+	Dir string
+}
+
+// Open returns the store at dir, or the directory the Ollama server uses
+// (OLLAMA_MODELS, ~/.ollama/models by default) when dir is empty.
+func Open(dir string) Store {
+	if dir == "" {
+		dir = envconfig.Models()
+	}
+
+	return Store{Dir: dir}
+}
+
+// BlobPath returns where the blob with the given digest is stored.
+func (s Store) BlobPath(digest string) string {
+	return filepath.Join(s.Dir, "blobs", strings.Replace(digest, ":", "-", 1))
+}
+
+// ManifestPath returns where the manifest of the model is stored. Names are
+// completed like Ollama does, e.g. "llama3.2" is
+// registry.ollama.ai/library/llama3.2/latest.
+func (s Store) ManifestPath(name string) string {
+	return filepath.Join(s.Dir, "manifests", filepath.FromSlash(fullName(name)))
+}
+
+// ReadManifest reads the manifest of the model.
+func (s Store) ReadManifest(name string) (Manifest, error) {
+	var m Manifest
+
+	data, err := os.ReadFile(s.ManifestPath(name))
+	if err != nil {
+		return m, err
+	}
+
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+// Models returns the names of every model with a manifest in the store, in
+// the short form Ollama lists them with (e.g. "llama3.2:latest").
+func (s Store) Models() ([]string, error) {
+	root := filepath.Join(s.Dir, "manifests")
+
+	var names []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 4 {
+			return nil
+		}
+
+		names = append(names, shortName(parts))
+		return nil
+	})
+
+	slices.Sort(names)
+
+	return names, err
+}
+
+// fullName expands a model name to host/namespace/model/tag.
+func fullName(name string) string {
+	host, namespace, tag := defaultHost, defaultNamespace, defaultTag
+
+	parts := strings.Split(name, "/")
+	model := parts[len(parts)-1]
+	switch len(parts) {
+	case 2:
+		namespace = parts[0]
+	case 3:
+		host, namespace = parts[0], parts[1]
+	}
+
+	if m, t, ok := strings.Cut(model, ":"); ok {
+		model, tag = m, t
+	}
+
+	return strings.Join([]string{host, namespace, model, tag}, "/")
+}
+
+// shortName drops the default host and namespace from a host, namespace,
+// model, tag tuple.
+func shortName(parts []string) string {
+	name := parts[2] + ":" + parts[3]
+	switch {
+	case parts[0] != defaultHost:
+		return parts[0] + "/" + parts[1] + "/" + name
+	case parts[1] != defaultNamespace:
+		return parts[1] + "/" + name
+	default:
+		return name
+	}
+}
+
+// Usage returns the total size of the blobs in the store, including the
+// ones kept in the trash.
+func (s Store) Usage() (int64, error) {
+	usage, err := dirSize(filepath.Join(s.Dir, "blobs"))
+	if err != nil {
+		return 0, err
+	}
+
+	trash, err := s.TrashUsage()
+	if err != nil {
+		return 0, err
+	}
+
+	return usage + trash, nil
+}
+
+// dirSize returns the size of the files under dir, 0 if it does not exist.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info, err := entry.Info(); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+
+	return size, err
+}
+
+// Reclaimer computes how much deleting a growing set of models frees, that
+// is the size of their blobs no other model uses. The references are only
+// read once, so models can be added one at a time cheaply.
+type Reclaimer struct {
+	store Store
+	// users counts, for every blob, the models using it that are not added
+	// yet.
+	users   map[string]int
+	removed map[string]bool
+	freed   int64
+}
+
+// NewReclaimer returns a Reclaimer with no model added.
+func (s Store) NewReclaimer() (*Reclaimer, error) {
+	refs, err := s.References()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]int, len(refs))
+	for digest, models := range refs {
+		users[digest] = len(models)
+	}
+
+	return &Reclaimer{store: s, users: users, removed: map[string]bool{}}, nil
+}
+
+// Add adds the model to the set and returns how much deleting the whole set
+// frees.
+func (r *Reclaimer) Add(name string) (int64, error) {
+	if r.removed[fullName(name)] {
+		return r.freed, nil
+	}
+
+	m, err := r.store.ReadManifest(name)
+	if err != nil {
+		return r.freed, err
+	}
+	r.removed[fullName(name)] = true
+
+	counted := map[string]bool{}
+	for _, layer := range m.Blobs() {
+		if counted[layer.Digest] {
+			continue
+		}
+		counted[layer.Digest] = true
+
+		r.users[layer.Digest]--
+		if r.users[layer.Digest] == 0 {
+			r.freed += layer.Size
+		}
+	}
+
+	return r.freed, nil
+}
+
+// Reclaimable returns how much deleting all the models would free, that is
+// the size of their blobs no other model uses.
+func (s Store) Reclaimable(names []string) (int64, error) {
+	r, err := s.NewReclaimer()
+	if err != nil {
+		return 0, err
+	}
+
+	var freed int64
+	for _, name := range names {
+		if freed, err = r.Add(name); err != nil {
+			return 0, err
+		}
+	}
+
+	return freed, nil
+}
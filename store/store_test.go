@@ -0,0 +1,72 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestReclaimable(t *testing.T) {
+	s := Store{Dir: t.TempDir()}
+	writeModel(t, s, "llama3.2", []byte("llama weights"))
+	writeModel(t, s, "team-model", []byte("llama weights"))
+	qwen := writeModel(t, s, "qwen2.5:7b", []byte("qwen weights"))
+
+	// every model shares the config blob
+	config := qwen.Config.Size
+
+	tests := []struct {
+		names []string
+		want  int64
+	}{
+		{nil, 0},
+		{[]string{"qwen2.5:7b"}, qwen.Layers[0].Size},
+		{[]string{"llama3.2"}, 0},
+		{[]string{"llama3.2", "llama3.2:latest"}, 0},
+		{[]string{"llama3.2", "team-model"}, int64(len("llama weights"))},
+		{[]string{"llama3.2", "team-model", "qwen2.5:7b"}, int64(len("llama weights")) + qwen.Layers[0].Size + config},
+	}
+
+	for _, tt := range tests {
+		got, err := s.Reclaimable(tt.names)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Reclaimable(%v) = %d, want %d", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestUsageCountsTrash(t *testing.T) {
+	s := Store{Dir: t.TempDir()}
+	writeModel(t, s, "llama3.2", []byte("llama weights"))
+	writeModel(t, s, "qwen2.5:7b", []byte("qwen weights"))
+
+	before, err := s.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Trash("qwen2.5:7b"); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := s.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	trash, err := s.TrashUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the trash also holds the manifest and the item
+	if after < before || trash < int64(len("qwen weights")) {
+		t.Fatalf("usage %d before, %d after trashing, trash %d", before, after, trash)
+	}
+
+	if err := s.EmptyTrash(); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := s.Usage(); after >= before {
+		t.Fatalf("usage %d after emptying the trash, %d before", after, before)
+	}
+}
@@ -0,0 +1,405 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// TrashItem is a soft-deleted model. Its manifest, and the blobs no other
+// model used when it was deleted, are kept in the trash until it is restored
+// or purged.
+type TrashItem struct {
+	ID        string    `json:"-"`
+	Model     string    `json:"model"`
+	DeletedAt time.Time `json:"deleted_at"`
+	// Size is the size of all the model's blobs, Freed the size of the ones
+	// moved to the trash.
+	Size  int64 `json:"size"`
+	Freed int64 `json:"freed"`
+}
+
+// TrashDir is where soft-deleted models are kept. It is inside the models
+// directory so that moving files there is a rename, and outside the
+// manifests and blobs directories so that Ollama ignores it.
+func (s Store) TrashDir() string {
+	return filepath.Join(s.Dir, "ollamanager-trash")
+}
+
+// TrashUsage returns the size of everything kept in the trash.
+func (s Store) TrashUsage() (int64, error) {
+	return dirSize(s.TrashDir())
+}
+
+// EmptyTrash purges every soft-deleted model.
+func (s Store) EmptyTrash() error {
+	return os.RemoveAll(s.TrashDir())
+}
+
+// Writable returns an error when models can't be moved in or out of the
+// store, e.g. when the directory belongs to the user the Ollama service runs
+// as.
+func (s Store) Writable() error {
+	for _, dir := range []string{"manifests", "blobs"} {
+		f, err := os.CreateTemp(filepath.Join(s.Dir, dir), ".ollamanager-*")
+		if err != nil {
+			return fmt.Errorf("%s is not writable: %s", filepath.Join(s.Dir, dir), err.Error())
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}
+
+	return nil
+}
+
+// References returns, for every blob, the models whose manifests use it.
+func (s Store) References() (map[string][]string, error) {
+	names, err := s.Models()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := map[string][]string{}
+	for _, name := range names {
+		m, err := s.ReadManifest(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, layer := range m.Blobs() {
+			if !slices.Contains(refs[layer.Digest], name) {
+				refs[layer.Digest] = append(refs[layer.Digest], name)
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+// SharedBlobs returns the blobs of the model that other installed models use
+// too, and would stay on disk if it was deleted.
+func (s Store) SharedBlobs(name string) ([]Layer, error) {
+	m, err := s.ReadManifest(name)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := s.References()
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []Layer
+	for _, layer := range m.Blobs() {
+		if len(refs[layer.Digest]) > 1 {
+			shared = append(shared, layer)
+		}
+	}
+
+	return shared, nil
+}
+
+// Trash soft-deletes the model: its manifest and the blobs no other model
+// uses are moved to the trash, so the model disappears from Ollama but can
+// be restored.
+func (s Store) Trash(name string) (TrashItem, error) {
+	m, err := s.ReadManifest(name)
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	shared, err := s.SharedBlobs(name)
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	item := TrashItem{
+		ID:        strconv.FormatInt(time.Now().UnixNano(), 10),
+		Model:     name,
+		DeletedAt: time.Now(),
+	}
+
+	var moved []Layer
+	for _, layer := range m.Blobs() {
+		item.Size += layer.Size
+		if slices.ContainsFunc(shared, func(l Layer) bool { return l.Digest == layer.Digest }) {
+			continue
+		}
+		item.Freed += layer.Size
+		moved = append(moved, layer)
+	}
+
+	dir := filepath.Join(s.TrashDir(), item.ID)
+	if err := os.MkdirAll(filepath.Join(dir, "blobs"), 0o755); err != nil {
+		return item, err
+	}
+
+	// the item is written first so that nothing moved to the trash is ever
+	// left without it
+	if err := writeTrashItem(dir, item); err != nil {
+		return item, err
+	}
+
+	if err := os.Rename(s.ManifestPath(name), filepath.Join(dir, "manifest")); err != nil {
+		os.RemoveAll(dir)
+		return item, err
+	}
+
+	for _, layer := range moved {
+		err := os.Rename(s.BlobPath(layer.Digest), s.trashBlobPath(item.ID, layer.Digest))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return item, err
+		}
+	}
+
+	return item, nil
+}
+
+// TrashItems returns the soft-deleted models, most recently deleted first.
+func (s Store) TrashItems() ([]TrashItem, error) {
+	entries, err := os.ReadDir(s.TrashDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []TrashItem
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		item, err := s.trashItem(entry.Name())
+		if err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	slices.SortFunc(items, func(a, b TrashItem) int {
+		return b.DeletedAt.Compare(a.DeletedAt)
+	})
+
+	return items, nil
+}
+
+// Restore moves a soft-deleted model back into the models directory. It
+// fails if a model with the same name was installed since, or if one of its
+// blobs can no longer be found.
+func (s Store) Restore(id string) (TrashItem, error) {
+	item, err := s.trashItem(id)
+	if err != nil {
+		return item, err
+	}
+
+	if _, err := os.Stat(s.ManifestPath(item.Model)); err == nil {
+		return item, fmt.Errorf("%s is installed again, delete it before restoring", item.Model)
+	}
+
+	m, err := s.trashManifest(id)
+	if err != nil {
+		return item, err
+	}
+
+	// find every blob before touching anything
+	sources := map[string]string{}
+	for _, layer := range m.Blobs() {
+		if _, err := os.Stat(s.BlobPath(layer.Digest)); err == nil {
+			continue
+		}
+		source, ok := s.findTrashedBlob(id, layer.Digest)
+		if !ok {
+			return item, fmt.Errorf("blob %s of %s is missing", layer.Digest, item.Model)
+		}
+		sources[layer.Digest] = source
+	}
+
+	for digest, source := range sources {
+		if filepath.Dir(filepath.Dir(source)) == filepath.Join(s.TrashDir(), id) {
+			err = os.Rename(source, s.BlobPath(digest))
+		} else {
+			// another trashed model still needs it
+			err = linkOrCopy(source, s.BlobPath(digest))
+		}
+		if err != nil {
+			return item, err
+		}
+	}
+
+	manifestPath := s.ManifestPath(item.Model)
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+		return item, err
+	}
+	if err := os.Rename(filepath.Join(s.TrashDir(), id, "manifest"), manifestPath); err != nil {
+		return item, err
+	}
+
+	return item, os.RemoveAll(filepath.Join(s.TrashDir(), id))
+}
+
+// Purge permanently deletes a soft-deleted model. Blobs another trashed model
+// still needs are handed over to it.
+func (s Store) Purge(id string) (TrashItem, error) {
+	item, err := s.trashItem(id)
+	if err != nil {
+		return item, err
+	}
+
+	items, err := s.TrashItems()
+	if err != nil {
+		return item, err
+	}
+
+	blobs, _ := os.ReadDir(filepath.Join(s.TrashDir(), id, "blobs"))
+	for _, blob := range blobs {
+		digest := digestFromBlobName(blob.Name())
+		for _, other := range items {
+			if other.ID == id || !s.trashNeeds(other.ID, digest) {
+				continue
+			}
+			if _, err := os.Stat(s.trashBlobPath(other.ID, digest)); err == nil {
+				break
+			}
+			if err := os.Rename(s.trashBlobPath(id, digest), s.trashBlobPath(other.ID, digest)); err != nil {
+				return item, err
+			}
+			break
+		}
+	}
+
+	return item, os.RemoveAll(filepath.Join(s.TrashDir(), id))
+}
+
+// PurgeExpired purges the models deleted more than maxAge ago.
+func (s Store) PurgeExpired(maxAge time.Duration) ([]TrashItem, error) {
+	items, err := s.TrashItems()
+	if err != nil {
+		return nil, err
+	}
+
+	var purged []TrashItem
+	for _, item := range items {
+		if time.Since(item.DeletedAt) < maxAge {
+			continue
+		}
+		if _, err := s.Purge(item.ID); err != nil {
+			return purged, err
+		}
+		purged = append(purged, item)
+	}
+
+	return purged, nil
+}
+
+func (s Store) trashItem(id string) (TrashItem, error) {
+	var item TrashItem
+
+	data, err := os.ReadFile(filepath.Join(s.TrashDir(), id, "item.json"))
+	if err != nil {
+		return item, err
+	}
+
+	if err := json.Unmarshal(data, &item); err != nil {
+		return item, err
+	}
+	item.ID = id
+
+	return item, nil
+}
+
+func (s Store) trashManifest(id string) (Manifest, error) {
+	var m Manifest
+
+	data, err := os.ReadFile(filepath.Join(s.TrashDir(), id, "manifest"))
+	if err != nil {
+		return m, err
+	}
+
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+func (s Store) trashBlobPath(id, digest string) string {
+	return filepath.Join(s.TrashDir(), id, "blobs", filepath.Base(s.BlobPath(digest)))
+}
+
+// trashNeeds reports whether the trashed model uses the blob.
+func (s Store) trashNeeds(id, digest string) bool {
+	m, err := s.trashManifest(id)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(m.Blobs(), func(l Layer) bool { return l.Digest == digest })
+}
+
+// findTrashedBlob looks for a blob in the trash item first, then in the
+// other trashed models.
+func (s Store) findTrashedBlob(id, digest string) (string, bool) {
+	if path := s.trashBlobPath(id, digest); fileExists(path) {
+		return path, true
+	}
+
+	entries, _ := os.ReadDir(s.TrashDir())
+	for _, entry := range entries {
+		if path := s.trashBlobPath(entry.Name(), digest); fileExists(path) {
+			return path, true
+		}
+	}
+
+	return "", false
+}
+
+func writeTrashItem(dir string, item TrashItem) error {
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "item.json"), data, 0o644)
+}
+
+func digestFromBlobName(name string) string {
+	if len(name) > 7 && name[:7] == "sha256-" {
+		return "sha256:" + name[7:]
+	}
+
+	return name
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func linkOrCopy(source, dest string) error {
+	if err := os.Link(source, dest); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/ollama/ollama/api"
+)
+
+type LayerStatus string
+
+const (
+	OK      LayerStatus = "ok"
+	MISSING LayerStatus = "missing"
+	CORRUPT LayerStatus = "corrupt"
+)
+
+// DamagedLayer is a blob of a model that is missing or does not match its
+// digest.
+type DamagedLayer struct {
+	Layer
+	Status LayerStatus
+}
+
+// Damage lists the damaged layers of a model.
+type Damage struct {
+	Model  string
+	Layers []DamagedLayer
+}
+
+// Verify re-hashes every blob referenced by the manifests of the models (all
+// installed models when names is empty) and compares it to its digest. Blobs
+// shared between models are only hashed once. It returns the models with
+// missing or corrupted layers.
+func (s Store) Verify(names []string, fn func(api.ProgressResponse) error) ([]Damage, error) {
+	if len(names) == 0 {
+		var err error
+		if names, err = s.Models(); err != nil {
+			return nil, err
+		}
+	}
+
+	checked := map[string]LayerStatus{}
+	var damaged []Damage
+
+	for _, name := range names {
+		m, err := s.ReadManifest(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest of %s: %s", name, err.Error())
+		}
+
+		damage := Damage{Model: name}
+		for _, layer := range m.Blobs() {
+			status, ok := checked[layer.Digest]
+			if !ok {
+				if status, err = s.verifyBlob(layer, fn); err != nil {
+					return nil, err
+				}
+				checked[layer.Digest] = status
+			}
+
+			if status != OK {
+				damage.Layers = append(damage.Layers, DamagedLayer{Layer: layer, Status: status})
+			}
+		}
+
+		if len(damage.Layers) > 0 {
+			damaged = append(damaged, damage)
+		}
+	}
+
+	return damaged, nil
+}
+
+func (s Store) verifyBlob(layer Layer, fn func(api.ProgressResponse) error) (LayerStatus, error) {
+	f, err := os.Open(s.BlobPath(layer.Digest))
+	if errors.Is(err, fs.ErrNotExist) {
+		return MISSING, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(h, NewProgressReader(f, "verifying "+shortDigest(layer.Digest), layer.Digest, info.Size(), fn))
+	if err != nil {
+		return "", err
+	}
+
+	if fmt.Sprintf("%x", h.Sum(nil)) != strings.TrimPrefix(layer.Digest, "sha256:") {
+		return CORRUPT, nil
+	}
+
+	return OK, nil
+}
+
+// RemoveBlob deletes a blob, e.g. a corrupted one so that the next pull
+// downloads it again instead of reusing it. A missing blob is not an error.
+func (s Store) RemoveBlob(digest string) error {
+	err := os.Remove(s.BlobPath(digest))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
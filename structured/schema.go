@@ -0,0 +1,237 @@
+package structured
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is the subset of JSON Schema Ollama turns into a grammar for the
+// format of a response. Other keywords are ignored.
+type Schema struct {
+	Type                 Types              `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+
+	// reject is set for "false", which no value matches, e.g. as
+	// additionalProperties.
+	reject  bool
+	pattern *regexp.Regexp
+}
+
+// Types are the allowed types of a value, a single type or a list of them
+// in JSON.
+type Types []string
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return errors.New("type must be a string or a list of strings")
+	}
+	*t = list
+	return nil
+}
+
+// UnmarshalJSON accepts the boolean schemas true and false.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*s = Schema{reject: !b}
+		return nil
+	}
+
+	type schema Schema
+	return json.Unmarshal(data, (*schema)(s))
+}
+
+// ParseSchema reads a JSON schema, compiling its patterns.
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema: %s", err.Error())
+	}
+
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (s *Schema) compile() error {
+	if s == nil {
+		return nil
+	}
+
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %s", s.Pattern, err.Error())
+		}
+		s.pattern = pattern
+	}
+
+	children := []*Schema{s.AdditionalProperties, s.Items}
+	children = append(children, s.AnyOf...)
+	for _, property := range s.Properties {
+		children = append(children, property)
+	}
+
+	for _, child := range children {
+		if err := child.compile(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate returns every way value, decoded from JSON, breaks the schema.
+// Each problem starts with the path of the value, e.g. "$.items[2].name".
+func (s *Schema) Validate(value any) []string {
+	return s.validate("$", value)
+}
+
+func (s *Schema) validate(path string, value any) []string {
+	if s == nil {
+		return nil
+	}
+	if s.reject {
+		return []string{path + ": not allowed"}
+	}
+
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(t string) bool { return hasType(value, t) }) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), typeOf(value))}
+	}
+
+	var problems []string
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return equal(e, value) }) {
+		problems = append(problems, fmt.Sprintf("%s: %s is not one of the allowed values", path, short(value)))
+	}
+
+	if len(s.AnyOf) > 0 && !slices.ContainsFunc(s.AnyOf, func(sub *Schema) bool { return len(sub.validate(path, value)) == 0 }) {
+		problems = append(problems, path+": matches none of anyOf")
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %s", path, name))
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			child := path + "." + name
+			if property, ok := s.Properties[name]; ok {
+				problems = append(problems, property.validate(child, v[name])...)
+			} else if s.AdditionalProperties != nil {
+				if s.AdditionalProperties.reject {
+					problems = append(problems, child+": unexpected property")
+				} else {
+					problems = append(problems, s.AdditionalProperties.validate(child, v[name])...)
+				}
+			}
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			problems = append(problems, fmt.Sprintf("%s: %d items, at least %d expected", path, len(v), *s.MinItems))
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			problems = append(problems, fmt.Sprintf("%s: %d items, at most %d expected", path, len(v), *s.MaxItems))
+		}
+		for i, item := range v {
+			problems = append(problems, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			problems = append(problems, fmt.Sprintf("%s: %d characters, at least %d expected", path, length, *s.MinLength))
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			problems = append(problems, fmt.Sprintf("%s: %d characters, at most %d expected", path, length, *s.MaxLength))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			problems = append(problems, fmt.Sprintf("%s: %s does not match %s", path, short(v), s.Pattern))
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			problems = append(problems, fmt.Sprintf("%s: %g is below the minimum %g", path, v, *s.Minimum))
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			problems = append(problems, fmt.Sprintf("%s: %g is above the maximum %g", path, v, *s.Maximum))
+		}
+	}
+
+	return problems
+}
+
+func hasType(value any, t string) bool {
+	switch t {
+	case "integer":
+		v, ok := value.(float64)
+		return ok && v == math.Trunc(v)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return typeOf(value) == t
+	}
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func equal(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
+
+// short formats a value for a problem, truncated to keep it on one line.
+func short(value any) string {
+	data, _ := json.Marshal(value)
+	if text := []rune(string(data)); len(text) > 40 {
+		return string(text[:37]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,143 @@
+package structured
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		value  string
+		want   []string
+	}{
+		// types
+		{name: "type", schema: `{"type": "string"}`, value: `"a"`},
+		{name: "type mismatch", schema: `{"type": "string"}`, value: `1`, want: []string{"$: expected string, got number"}},
+		{name: "type union", schema: `{"type": ["string", "null"]}`, value: `null`},
+		{name: "type union mismatch", schema: `{"type": ["string", "null"]}`, value: `true`, want: []string{"$: expected string or null, got boolean"}},
+		{name: "integer", schema: `{"type": "integer"}`, value: `3`},
+		{name: "integer as float", schema: `{"type": "integer"}`, value: `3.0`},
+		{name: "fraction is not an integer", schema: `{"type": "integer"}`, value: `3.5`, want: []string{"$: expected integer, got number"}},
+		{name: "integer is a number", schema: `{"type": "number"}`, value: `3`},
+		{name: "no type", schema: `{}`, value: `{"a": [1]}`},
+
+		// objects
+		{
+			name:   "required",
+			schema: `{"type": "object", "required": ["name", "capital"]}`,
+			value:  `{"name": "France"}`,
+			want:   []string{"$: missing required property capital"},
+		},
+		{
+			name:   "additionalProperties false",
+			schema: `{"type": "object", "properties": {"name": {"type": "string"}}, "additionalProperties": false}`,
+			value:  `{"name": "France", "motto": "Liberté", "area": 1}`,
+			want:   []string{"$.area: unexpected property", "$.motto: unexpected property"},
+		},
+		{
+			name:   "additionalProperties schema",
+			schema: `{"type": "object", "properties": {"name": {"type": "string"}}, "additionalProperties": {"type": "integer"}}`,
+			value:  `{"name": "France", "area": 1, "motto": "Liberté"}`,
+			want:   []string{"$.motto: expected integer, got string"},
+		},
+		{
+			name:   "additionalProperties allowed by default",
+			schema: `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+			value:  `{"name": "France", "motto": "Liberté"}`,
+		},
+
+		// arrays
+		{
+			name:   "items",
+			schema: `{"type": "array", "items": {"type": "string"}}`,
+			value:  `["fr", 2, "de", null]`,
+			want:   []string{"$[1]: expected string, got number", "$[3]: expected string, got null"},
+		},
+		{name: "minItems", schema: `{"type": "array", "minItems": 2}`, value: `[1]`, want: []string{"$: 1 items, at least 2 expected"}},
+		{name: "maxItems", schema: `{"type": "array", "maxItems": 1}`, value: `[1, 2]`, want: []string{"$: 2 items, at most 1 expected"}},
+
+		// enum and anyOf
+		{name: "enum", schema: `{"enum": ["celsius", "fahrenheit", 0]}`, value: `0`},
+		{name: "enum mismatch", schema: `{"enum": ["celsius", "fahrenheit"]}`, value: `"kelvin"`, want: []string{`$: "kelvin" is not one of the allowed values`}},
+		{name: "enum object", schema: `{"enum": [{"a": 1, "b": 2}]}`, value: `{"b": 2, "a": 1}`},
+		{name: "anyOf", schema: `{"anyOf": [{"type": "string"}, {"type": "integer", "minimum": 0}]}`, value: `4`},
+		{
+			name:   "anyOf mismatch",
+			schema: `{"anyOf": [{"type": "string"}, {"type": "integer", "minimum": 0}]}`,
+			value:  `-4`,
+			want:   []string{"$: matches none of anyOf"},
+		},
+
+		// numbers
+		{name: "minimum", schema: `{"type": "number", "minimum": 0}`, value: `-0.5`, want: []string{"$: -0.5 is below the minimum 0"}},
+		{name: "maximum", schema: `{"type": "number", "maximum": 100}`, value: `100.5`, want: []string{"$: 100.5 is above the maximum 100"}},
+		{name: "bounds inclusive", schema: `{"type": "number", "minimum": 0, "maximum": 100}`, value: `100`},
+
+		// strings
+		{name: "minLength in runes", schema: `{"type": "string", "minLength": 3}`, value: `"été"`},
+		{name: "minLength", schema: `{"type": "string", "minLength": 4}`, value: `"été"`, want: []string{"$: 3 characters, at least 4 expected"}},
+		{name: "maxLength in runes", schema: `{"type": "string", "maxLength": 2}`, value: `"日本"`},
+		{name: "maxLength", schema: `{"type": "string", "maxLength": 1}`, value: `"日本"`, want: []string{"$: 2 characters, at most 1 expected"}},
+		{name: "pattern", schema: `{"type": "string", "pattern": "^[A-Z]{2}$"}`, value: `"FR"`},
+		{name: "pattern mismatch", schema: `{"type": "string", "pattern": "^[A-Z]{2}$"}`, value: `"fr"`, want: []string{`$: "fr" does not match ^[A-Z]{2}$`}},
+
+		// paths
+		{
+			name: "nested path",
+			schema: `{"type": "object", "properties": {"a": {"type": "array", "items": {
+				"type": "object", "required": ["b"], "properties": {"b": {"type": "integer"}}}}}}`,
+			value: `{"a": [{"b": 1}, {"b": 2}, {"b": "3"}, {}]}`,
+			want:  []string{"$.a[2].b: expected integer, got string", "$.a[3]: missing required property b"},
+		},
+		{
+			name:   "false schema",
+			schema: `{"type": "object", "properties": {"legacy": false}}`,
+			value:  `{"legacy": 1}`,
+			want:   []string{"$.legacy: not allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSchema([]byte(tt.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var value any
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := s.Validate(value); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate(%s) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := map[string]string{
+		"not json":          `{`,
+		"bad type":          `{"type": 1}`,
+		"bad pattern":       `{"type": "string", "pattern": "("}`,
+		"bad nested regexp": `{"properties": {"a": {"items": {"pattern": "[a-"}}}}`,
+	}
+
+	for name, schema := range tests {
+		if _, err := ParseSchema([]byte(schema)); err == nil {
+			t.Errorf("%s: ParseSchema(%s) succeeded", name, schema)
+		}
+	}
+}
+
+func TestShortKeepsRunes(t *testing.T) {
+	got := short(strings.Repeat("日本", 30))
+	if !strings.HasSuffix(got, "...") || len([]rune(got)) != 40 {
+		t.Errorf("short() = %q, want 37 runes and an ellipsis", got)
+	}
+}
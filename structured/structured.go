@@ -0,0 +1,252 @@
+// Package structured checks how reliably a model follows a JSON schema given
+// as the format of its responses.
+package structured
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/ollama/ollama/api"
+)
+
+// Attempt is the outcome of one request.
+type Attempt struct {
+	Run      int           `json:"run"`
+	Output   string        `json:"output"`
+	Problems []string      `json:"problems,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Valid reports whether the output conforms to the schema.
+func (a Attempt) Valid() bool {
+	return len(a.Problems) == 0
+}
+
+// Result holds every attempt with the same prompt and schema.
+type Result struct {
+	Model    string    `json:"model"`
+	Attempts []Attempt `json:"attempts"`
+}
+
+// Valid returns how many attempts conform to the schema.
+func (r Result) Valid() int {
+	valid := 0
+	for _, a := range r.Attempts {
+		if a.Valid() {
+			valid++
+		}
+	}
+	return valid
+}
+
+// Rate returns the share of attempts conforming to the schema, from 0 to 1.
+func (r Result) Rate() float64 {
+	if len(r.Attempts) == 0 {
+		return 0
+	}
+	return float64(r.Valid()) / float64(len(r.Attempts))
+}
+
+// Problem is a validation problem and the number of attempts it occurred in.
+type Problem struct {
+	Text  string
+	Count int
+}
+
+// Problems returns the distinct problems of the attempts, most frequent
+// first.
+func (r Result) Problems() []Problem {
+	counts := map[string]int{}
+	for _, a := range r.Attempts {
+		for _, p := range a.Problems {
+			counts[p]++
+		}
+	}
+
+	var problems []Problem
+	for text, count := range counts {
+		problems = append(problems, Problem{Text: text, Count: count})
+	}
+	slices.SortFunc(problems, func(a, b Problem) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Text, b.Text)
+	})
+
+	return problems
+}
+
+// Run sends the prompt with the schema as format runs times, validating
+// every response. A failed request counts as an attempt not conforming to the
+// schema, only cancelling ctx stops the runs. fn (when not nil) is called
+// after every attempt.
+func Run(ctx context.Context, host *url.URL, model, prompt string, schema json.RawMessage, options map[string]any, runs int, fn func(a Attempt)) (Result, error) {
+	result := Result{Model: model}
+
+	s, err := ParseSchema(schema)
+	if err != nil {
+		return result, err
+	}
+	if runs <= 0 {
+		return result, errors.New("the number of runs must be positive")
+	}
+
+	for run := range runs {
+		start := time.Now()
+		output, err := Chat(ctx, host, model, prompt, schema, options)
+		if err != nil && ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		a := Attempt{
+			Run:      run + 1,
+			Output:   output,
+			Duration: time.Since(start),
+		}
+
+		var value any
+		if err != nil {
+			a.Problems = []string{"request failed: " + err.Error()}
+		} else if err := json.Unmarshal([]byte(output), &value); err != nil {
+			a.Problems = []string{"not valid JSON: " + err.Error()}
+		} else {
+			a.Problems = s.Validate(value)
+		}
+
+		result.Attempts = append(result.Attempts, a)
+		if fn != nil {
+			fn(a)
+		}
+	}
+
+	return result, nil
+}
+
+// client sends the chat requests. The timeout covers loading the model and
+// generating the whole response, so a stuck server fails the attempt instead
+// of hanging the run.
+var client = &http.Client{Timeout: 5 * time.Minute}
+
+// Chat sends the prompt with the schema as the format of the response and
+// returns the response. The request is made directly, as the api client
+// only sends "json" as a format.
+func Chat(ctx context.Context, host *url.URL, model, prompt string, schema json.RawMessage, options map[string]any) (string, error) {
+	stream := false
+	body, err := json.Marshal(struct {
+		Model    string          `json:"model"`
+		Messages []api.Message   `json:"messages"`
+		Format   json.RawMessage `json:"format"`
+		Options  map[string]any  `json:"options,omitempty"`
+		Stream   *bool           `json:"stream"`
+	}{
+		Model:    model,
+		Messages: []api.Message{{Role: "user", Content: prompt}},
+		Format:   schema,
+		Options:  options,
+		Stream:   &stream,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, host.JoinPath("api", "chat").String(), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return "", errors.New(apiErr.Error)
+		}
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var chat api.ChatResponse
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return "", err
+	}
+
+	return chat.Message.Content, nil
+}
+
+// ExampleSchema is offered when no schema was used yet.
+const ExampleSchema = `{
+  "type": "object",
+  "required": ["name", "capital", "population"],
+  "properties": {
+    "name": {"type": "string"},
+    "capital": {"type": "string"},
+    "population": {"type": "integer", "minimum": 0},
+    "languages": {"type": "array", "items": {"type": "string"}}
+  },
+  "additionalProperties": false
+}
+`
+
+// SchemaPath returns where the schema last used is kept, in the config dir.
+func SchemaPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "schema.json"), nil
+}
+
+// LoadSchema returns the schema last saved, or ExampleSchema.
+func LoadSchema() (string, error) {
+	path, err := SchemaPath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ExampleSchema, nil
+	}
+
+	return string(data), err
+}
+
+// SaveSchema keeps the schema to offer it next time.
+func SaveSchema(data string) error {
+	path, err := SchemaPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(data), 0o644)
+}
@@ -0,0 +1,92 @@
+package structured
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testSchema = `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`
+
+// chatServer answers the chat requests with the responses in turn, failing
+// the requests whose response is empty.
+func chatServer(t *testing.T, responses ...string) *url.URL {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := responses[int(calls.Add(1)-1)%len(responses)]
+		if response == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "model crashed"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"message": map[string]string{"role": "assistant", "content": response},
+			"done":    true,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	host, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return host
+}
+
+func TestRunRecordsFailedRequests(t *testing.T) {
+	host := chatServer(t, `{"name": "France"}`, "", `{"name": 1}`)
+
+	result, err := Run(context.Background(), host, "llama3.2", "a country", []byte(testSchema), nil, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Attempts) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(result.Attempts))
+	}
+	if result.Valid() != 1 {
+		t.Errorf("Valid() = %d, want 1", result.Valid())
+	}
+	if problems := result.Attempts[1].Problems; len(problems) != 1 || !strings.Contains(problems[0], "model crashed") {
+		t.Errorf("failed attempt problems = %q, want the request error", problems)
+	}
+}
+
+func TestRunStopsWhenCancelled(t *testing.T) {
+	host := chatServer(t, `{"name": "France"}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Run(ctx, host, "llama3.2", "a country", []byte(testSchema), nil, 3, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestChatTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	timeout := client.Timeout
+	client.Timeout = 50 * time.Millisecond
+	defer func() { client.Timeout = timeout }()
+
+	host, _ := url.Parse(server.URL)
+	if _, err := Chat(context.Background(), host, "llama3.2", "a country", []byte(testSchema), nil); err == nil {
+		t.Error("Chat() did not time out")
+	}
+}
@@ -6,11 +6,27 @@ type (
 )
 
 const (
-	INSTALL Tab = "Install"
-	MONITOR Tab = "Monitor"
-	MANAGE  Tab = "Manage"
+	INSTALL  Tab = "Install"
+	MONITOR  Tab = "Monitor"
+	MANAGE   Tab = "Manage"
+	TRASH    Tab = "Trash"
+	HISTORY  Tab = "History"
+	SESSIONS Tab = "Sessions"
 
 	CHAT   ManageAction = "Chat"
 	UPDATE ManageAction = "Update"
 	DELETE ManageAction = "Delete"
+	CREATE ManageAction = "Create"
+	IMPORT ManageAction = "Import"
+	COPY   ManageAction = "Copy"
+	PUSH   ManageAction = "Push"
+	PIN    ManageAction = "Pin"
+
+	BENCHMARK ManageAction = "Benchmark"
+	COMPARE   ManageAction = "Compare"
+	EMBED     ManageAction = "Embed"
+	LOAD      ManageAction = "Load"
+	TOOLS     ManageAction = "Tools"
+	SCHEMA    ManageAction = "Schema"
+	TEMPLATE  ManageAction = "Template"
 )
@@ -0,0 +1,172 @@
+// Package timeline keeps the recent history of the models loaded by the
+// Ollama server, sampled periodically in a ring buffer, to chart memory use
+// over the last minutes.
+package timeline
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// Model is a loaded model at the time of a sample.
+type Model struct {
+	Name     string
+	Size     int64
+	SizeVRAM int64
+}
+
+// Sample is a snapshot of the loaded models.
+type Sample struct {
+	Time   time.Time
+	Models []Model
+}
+
+// Size returns the total size of the loaded models.
+func (s Sample) Size() int64 {
+	var size int64
+	for _, model := range s.Models {
+		size += model.Size
+	}
+	return size
+}
+
+// VRAM returns the VRAM used by the named model, 0 when it wasn't loaded.
+func (s Sample) VRAM(name string) int64 {
+	for _, model := range s.Models {
+		if model.Name == name {
+			return model.SizeVRAM
+		}
+	}
+	return 0
+}
+
+// Ring holds the samples of the last window, taken every interval, dropping
+// the oldest once full. It is safe for concurrent use.
+type Ring struct {
+	window   time.Duration
+	interval time.Duration
+
+	mu      sync.Mutex
+	samples []Sample
+	next    int
+	full    bool
+}
+
+// NewRing returns a ring keeping window worth of samples taken every
+// interval.
+func NewRing(window, interval time.Duration) *Ring {
+	return &Ring{
+		window:   window,
+		interval: interval,
+		samples:  make([]Sample, max(int(window/interval), 1)),
+	}
+}
+
+// Window returns how far back the samples go once the ring is full.
+func (r *Ring) Window() time.Duration {
+	return r.window
+}
+
+// Add stores the sample, replacing the oldest one when the ring is full.
+func (r *Ring) Add(s Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Samples returns the stored samples, oldest first.
+func (r *Ring) Samples() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]Sample(nil), r.samples[:r.next]...)
+	}
+
+	return append(append([]Sample(nil), r.samples[r.next:]...), r.samples[:r.next]...)
+}
+
+// Poll samples the running models once.
+func (r *Ring) Poll(ctx context.Context, client *api.Client) error {
+	running, err := client.ListRunning(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := Sample{Time: time.Now()}
+	for _, model := range running.Models {
+		s.Models = append(s.Models, Model{
+			Name:     model.Name,
+			Size:     model.Size,
+			SizeVRAM: model.SizeVRAM,
+		})
+	}
+
+	r.Add(s)
+
+	return nil
+}
+
+// Run samples every interval of the ring until the context is cancelled.
+// Failed polls are skipped.
+func (r *Ring) Run(ctx context.Context, client *api.Client) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		_ = r.Poll(ctx, client)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Series splits the time from since to until into columns and returns the
+// largest value of the samples in each, e.g. to draw a sparkline. Columns
+// before the first sample are NaN, columns without a sample of their own
+// repeat the previous one. There are no columns when columns is not
+// positive, e.g. in a terminal too narrow to draw them.
+func Series(samples []Sample, since, until time.Time, columns int, value func(Sample) float64) []float64 {
+	if columns <= 0 {
+		return nil
+	}
+
+	series := make([]float64, columns)
+	seen := make([]bool, columns)
+	step := until.Sub(since) / time.Duration(columns)
+
+	for _, s := range samples {
+		if s.Time.Before(since) || s.Time.After(until) || step <= 0 {
+			continue
+		}
+
+		i := min(int(s.Time.Sub(since)/step), columns-1)
+		if v := value(s); !seen[i] || v > series[i] {
+			series[i] = v
+		}
+		seen[i] = true
+	}
+
+	previous := math.NaN()
+	for i := range series {
+		if seen[i] {
+			previous = series[i]
+		} else {
+			series[i] = previous
+		}
+	}
+
+	return series
+}
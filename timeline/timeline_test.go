@@ -0,0 +1,49 @@
+package timeline
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSeries(t *testing.T) {
+	since := time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC)
+	until := since.Add(4 * time.Minute)
+
+	samples := []Sample{
+		{Time: since.Add(time.Minute), Models: []Model{{Name: "llama3.2:latest", Size: 2}}},
+		{Time: since.Add(90 * time.Second), Models: []Model{{Name: "llama3.2:latest", Size: 3}}},
+		{Time: since.Add(3 * time.Minute)},
+	}
+	size := func(s Sample) float64 {
+		var total int64
+		for _, m := range s.Models {
+			total += m.Size
+		}
+		return float64(total)
+	}
+
+	got := Series(samples, since, until, 4, size)
+	want := []float64{math.NaN(), 3, 3, 0}
+
+	if len(got) != len(want) {
+		t.Fatalf("Series() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] && !(math.IsNaN(got[i]) && math.IsNaN(want[i])) {
+			t.Errorf("Series() = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestSeriesWithoutColumns(t *testing.T) {
+	since := time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC)
+	samples := []Sample{{Time: since.Add(time.Minute)}}
+
+	for _, columns := range []int{0, -3} {
+		if got := Series(samples, since, since.Add(time.Hour), columns, func(Sample) float64 { return 1 }); len(got) != 0 {
+			t.Errorf("Series() with %d columns = %v, want none", columns, got)
+		}
+	}
+}
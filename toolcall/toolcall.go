@@ -0,0 +1,168 @@
+// Package toolcall runs chats with tool definitions and checks the tool
+// calls models make, to qualify them for agent work.
+package toolcall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/ollama/ollama/api"
+)
+
+// Example is offered when no tools were defined yet.
+const Example = `[
+  {
+    "type": "function",
+    "function": {
+      "name": "get_current_weather",
+      "description": "Get the current weather for a city",
+      "parameters": {
+        "type": "object",
+        "required": ["city"],
+        "properties": {
+          "city": {"type": "string", "description": "Name of the city"},
+          "unit": {"type": "string", "description": "Temperature unit", "enum": ["celsius", "fahrenheit"]}
+        }
+      }
+    }
+  }
+]
+`
+
+// Parse reads a JSON array of tools in the format of the chat API. The type
+// defaults to "function".
+func Parse(data []byte) (api.Tools, error) {
+	var tools api.Tools
+	if err := json.Unmarshal(data, &tools); err != nil {
+		return nil, fmt.Errorf("invalid tools: %s", err.Error())
+	}
+
+	if len(tools) == 0 {
+		return nil, errors.New("define at least one tool")
+	}
+
+	var names []string
+	for i := range tools {
+		if tools[i].Type == "" {
+			tools[i].Type = "function"
+		}
+
+		name := tools[i].Function.Name
+		if name == "" {
+			return nil, fmt.Errorf("tool %d has no name", i+1)
+		}
+		if slices.Contains(names, name) {
+			return nil, fmt.Errorf("tool %s is defined twice", name)
+		}
+		names = append(names, name)
+	}
+
+	return tools, nil
+}
+
+// Path returns where the tools last used are kept, in the config dir.
+func Path() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "tools.json"), nil
+}
+
+// Load returns the tools JSON last saved, or Example.
+func Load() (string, error) {
+	path, err := Path()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Example, nil
+	}
+
+	return string(data), err
+}
+
+// Save keeps the tools JSON to offer it next time.
+func Save(data string) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(data), 0o644)
+}
+
+// Chat sends the messages with the tools and returns the reply, with the
+// tool calls of every streamed chunk. fn (when not nil) is called with every
+// chunk of content.
+func Chat(ctx context.Context, client *api.Client, model string, messages []api.Message, tools api.Tools, options map[string]any, fn func(chunk string)) (api.Message, error) {
+	reply := api.Message{Role: "assistant"}
+	var content strings.Builder
+
+	req := &api.ChatRequest{
+		Model:    model,
+		Messages: messages,
+		Tools:    tools,
+		Options:  options,
+	}
+
+	err := client.Chat(ctx, req, func(resp api.ChatResponse) error {
+		content.WriteString(resp.Message.Content)
+		reply.ToolCalls = append(reply.ToolCalls, resp.Message.ToolCalls...)
+		if fn != nil && resp.Message.Content != "" {
+			fn(resp.Message.Content)
+		}
+		return nil
+	})
+
+	reply.Content = content.String()
+
+	return reply, err
+}
+
+// Check returns what is wrong with a tool call: an undefined tool, missing
+// required arguments, unknown arguments or values outside an enum.
+func Check(tools api.Tools, call api.ToolCall) []string {
+	i := slices.IndexFunc(tools, func(t api.Tool) bool { return t.Function.Name == call.Function.Name })
+	if i < 0 {
+		return []string{"no tool named " + call.Function.Name}
+	}
+	params := tools[i].Function.Parameters
+
+	var problems []string
+	for _, name := range params.Required {
+		if _, ok := call.Function.Arguments[name]; !ok {
+			problems = append(problems, "missing required argument "+name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(call.Function.Arguments)) {
+		property, ok := params.Properties[name]
+		if !ok {
+			problems = append(problems, "unknown argument "+name)
+			continue
+		}
+		value := fmt.Sprint(call.Function.Arguments[name])
+		if len(property.Enum) > 0 && !slices.Contains(property.Enum, value) {
+			problems = append(problems, fmt.Sprintf("%s is %q, not one of %s", name, value, strings.Join(property.Enum, ", ")))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,105 @@
+package toolcall
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "example", data: Example},
+		{name: "type defaults to function", data: `[{"function": {"name": "search"}}]`},
+		{name: "not json", data: `[{`, wantErr: "invalid tools"},
+		{name: "not a list", data: `{"function": {"name": "search"}}`, wantErr: "invalid tools"},
+		{name: "empty", data: `[]`, wantErr: "at least one tool"},
+		{name: "empty name", data: `[{"function": {"name": "search"}}, {"function": {"name": ""}}]`, wantErr: "tool 2 has no name"},
+		{name: "duplicate", data: `[{"function": {"name": "search"}}, {"function": {"name": "search"}}]`, wantErr: "search is defined twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools, err := Parse([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tool := range tools {
+				if tool.Type != "function" {
+					t.Errorf("type = %q, want function", tool.Type)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tools, err := Parse([]byte(Example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		call api.ToolCallFunction
+		want []string
+	}{
+		{
+			name: "valid",
+			call: api.ToolCallFunction{Name: "get_current_weather", Arguments: api.ToolCallFunctionArguments{"city": "Paris", "unit": "celsius"}},
+		},
+		{
+			name: "optional argument left out",
+			call: api.ToolCallFunction{Name: "get_current_weather", Arguments: api.ToolCallFunctionArguments{"city": "Paris"}},
+		},
+		{
+			name: "undefined tool",
+			call: api.ToolCallFunction{Name: "get_weather", Arguments: api.ToolCallFunctionArguments{"city": "Paris"}},
+			want: []string{"no tool named get_weather"},
+		},
+		{
+			name: "missing required argument",
+			call: api.ToolCallFunction{Name: "get_current_weather", Arguments: api.ToolCallFunctionArguments{"unit": "celsius"}},
+			want: []string{"missing required argument city"},
+		},
+		{
+			name: "unknown arguments",
+			call: api.ToolCallFunction{Name: "get_current_weather", Arguments: api.ToolCallFunctionArguments{"city": "Paris", "days": 3, "country": "FR"}},
+			want: []string{"unknown argument country", "unknown argument days"},
+		},
+		{
+			name: "enum mismatch",
+			call: api.ToolCallFunction{Name: "get_current_weather", Arguments: api.ToolCallFunctionArguments{"city": "Paris", "unit": "kelvin"}},
+			want: []string{`unit is "kelvin", not one of celsius, fahrenheit`},
+		},
+		{
+			name: "several problems",
+			call: api.ToolCallFunction{Name: "get_current_weather", Arguments: api.ToolCallFunctionArguments{"unit": "Celsius", "town": "Paris"}},
+			want: []string{
+				"missing required argument city",
+				"unknown argument town",
+				`unit is "Celsius", not one of celsius, fahrenheit`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Check(tools, api.ToolCall{Function: tt.call})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/v2/spinner"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/gaurav-gosain/ollamanager/benchmark"
+	"github.com/muesli/reflow/truncate"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+)
+
+// CompareResult is the response of one model to the compared prompt.
+type CompareResult struct {
+	Model  string
+	Output string
+	Sample benchmark.Sample
+	Err    error
+	Done   bool
+}
+
+// Stats formats the latency and token stats of the response.
+func (r CompareResult) Stats() string {
+	if r.Err != nil {
+		return "error: " + r.Err.Error()
+	}
+	if r.Output == "" && !r.Done {
+		return "waiting for the first token..."
+	}
+
+	stats := fmt.Sprintf("first token %d ms", r.Sample.TimeToFirstToken.Milliseconds())
+	if !r.Done {
+		return stats + " • streaming..."
+	}
+
+	return fmt.Sprintf(
+		"%s • %.1f tokens/s • %d tokens • %s total",
+		stats,
+		r.Sample.TokensPerSecond(),
+		r.Sample.EvalCount,
+		r.Sample.TotalDuration.Round(100*time.Millisecond),
+	)
+}
+
+// CompareChunkMsg carries a streamed chunk of the response of the model at
+// Index.
+type CompareChunkMsg struct {
+	Index int
+	Chunk string
+}
+
+// CompareDoneMsg ends the response of the model at Index.
+type CompareDoneMsg struct {
+	Index  int
+	Sample benchmark.Sample
+	Err    error
+}
+
+// CompareModel streams the responses of several models to the same prompt in
+// adjacent panes.
+type CompareModel struct {
+	Prompt string
+	// Options are the generation options sent with the prompt.
+	Options map[string]any
+	Results []CompareResult
+	spinner spinner.Model
+	start   time.Time
+	width   int
+	height  int
+	// scroll is how many lines the panes are scrolled up from the end of the
+	// responses.
+	scroll int
+}
+
+func NewCompareModel(prompt string, models []string) CompareModel {
+	results := make([]CompareResult, len(models))
+	for i, model := range models {
+		results[i].Model = model
+	}
+
+	return CompareModel{
+		Prompt:  prompt,
+		Results: results,
+		spinner: InitSpinner(),
+		start:   time.Now(),
+	}
+}
+
+// Apply records a comparison message in the results. It is called by Update
+// and can be used to collect the results without a program.
+func (m *CompareModel) Apply(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case CompareChunkMsg:
+		result := &m.Results[msg.Index]
+		if result.Output == "" {
+			// replaced by the time measured by the request once it is done
+			result.Sample.TimeToFirstToken = time.Since(m.start)
+		}
+		result.Output += msg.Chunk
+	case CompareDoneMsg:
+		m.Results[msg.Index].Sample = msg.Sample
+		m.Results[msg.Index].Err = msg.Err
+		m.Results[msg.Index].Done = true
+	}
+}
+
+func (m CompareModel) Init() (tea.Model, tea.Cmd) {
+	return m, m.spinner.Tick
+}
+
+func (m CompareModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			m.scroll++
+		case "down", "j":
+			m.scroll = max(m.scroll-1, 0)
+		case "end", "G":
+			m.scroll = 0
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case CompareChunkMsg, CompareDoneMsg:
+		m.Apply(msg)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m CompareModel) View() string {
+	if m.width == 0 || len(m.Results) == 0 {
+		return ""
+	}
+
+	// truncated by display width, so wide runes are never cut in half
+	prompt := truncate.StringWithTail(strings.ReplaceAll(m.Prompt, "\n", " "), uint(max(m.width-12, 0)), "...")
+	header := lipgloss.NewStyle().Padding(0, 1).Render(StatusStyle.Render("Prompt") + " " + prompt)
+	footer := lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("242")).
+		Render("↑/k ↓/j scroll • G follow • q quit")
+
+	paneWidth := m.width / len(m.Results)
+	paneHeight := m.height - lipgloss.Height(header) - lipgloss.Height(footer)
+
+	panes := make([]string, len(m.Results))
+	for i, result := range m.Results {
+		panes[i] = m.pane(result, paneWidth, paneHeight)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		lipgloss.JoinHorizontal(lipgloss.Top, panes...),
+		footer,
+	)
+}
+
+// pane renders a response in a box of the given outer size, showing the end
+// of the output unless scrolled up.
+func (m CompareModel) pane(result CompareResult, width, height int) string {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("69")).
+		Padding(0, 1)
+	textWidth := max(width-style.GetHorizontalFrameSize(), 1)
+	textHeight := max(height-style.GetVerticalFrameSize(), 3)
+
+	title := StatusStyle.Render(result.Model)
+	if !result.Done {
+		title = m.spinner.View() + " " + title
+	}
+
+	stats := lipgloss.NewStyle().Foreground(lipgloss.Color("242")).
+		Render(wordwrap.String(result.Stats(), textWidth))
+	if result.Err != nil {
+		stats = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87")).
+			Render(wrap.String(result.Stats(), textWidth))
+	}
+
+	bodyHeight := max(textHeight-lipgloss.Height(title)-lipgloss.Height(stats)-2, 1)
+
+	lines := strings.Split(wrap.String(wordwrap.String(result.Output, textWidth), textWidth), "\n")
+	end := max(len(lines)-min(m.scroll, max(len(lines)-bodyHeight, 0)), 0)
+	start := max(end-bodyHeight, 0)
+	body := strings.Join(lines[start:end], "\n")
+
+	return style.
+		Width(width - 2).
+		Height(height - 2).
+		Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			"",
+			lipgloss.NewStyle().Height(bodyHeight).Render(body),
+			"",
+			stats,
+		))
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCompareViewTruncatesPromptByWidth(t *testing.T) {
+	prompts := []string{
+		strings.Repeat("日本語のプロンプト", 10),
+		strings.Repeat("héllo wörld ", 10),
+		"short prompt",
+	}
+
+	for _, prompt := range prompts {
+		var m tea.Model = NewCompareModel(prompt, []string{"llama3.2", "qwen2.5:7b"})
+		m, _ = m.Update(tea.WindowSizeMsg{Width: 41, Height: 20})
+
+		header := strings.Split(m.View(), "\n")[0]
+		if !utf8.ValidString(header) {
+			t.Errorf("header cuts a rune: %q", header)
+		}
+		if width := ansi.StringWidth(header); width > 41 {
+			t.Errorf("header is %d cells wide, want at most 41: %q", width, header)
+		}
+		if len([]rune(prompt)) < 20 && !strings.Contains(header, prompt) {
+			t.Errorf("short prompt was truncated: %q", header)
+		}
+	}
+}
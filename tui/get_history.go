@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/gaurav-gosain/ollamanager/history"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+type HistoryEntry history.Entry
+
+func GetHistory() ([]HistoryEntry, error) {
+	path, err := history.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := history.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	historyEntries := make([]HistoryEntry, len(entries))
+	for i, entry := range entries {
+		historyEntries[i] = HistoryEntry(entry)
+	}
+
+	return historyEntries, nil
+}
+
+func (entry HistoryEntry) Title() string {
+	return entry.Action + " " + entry.Model
+}
+
+func (entry HistoryEntry) Description() string {
+	parts := []string{
+		string(entry.Outcome),
+		relTime(entry.Time),
+		entry.Duration.Round(100 * time.Millisecond).String(),
+	}
+	if entry.Bytes > 0 {
+		parts = append(parts, humanize.Bytes(uint64(entry.Bytes)))
+	}
+
+	return strings.Join(parts, " • ")
+}
+
+// FilterValue lets the list filter match on the action, model, outcome, host
+// and user at once, e.g. "delete llama" or "failure".
+func (entry HistoryEntry) FilterValue() string {
+	return fmt.Sprintf(
+		"%s %s %s %s %s",
+		entry.Action, entry.Model, entry.Outcome, entry.Host, entry.User,
+	)
+}
+
+// historyInfo renders the info pane for a history entry.
+func historyInfo(entry HistoryEntry, width int) string {
+	outcomeStyle := tagStyle
+	outcomeBorder := tagBorder
+	if entry.Outcome == history.SUCCESS {
+		outcomeStyle = titleStyle.Render
+		outcomeBorder = titleBorder
+	}
+
+	digest := "-"
+	if entry.DigestBefore != entry.DigestAfter {
+		digest = shortDigest(entry.DigestBefore) + " → " + shortDigest(entry.DigestAfter)
+	} else if entry.DigestAfter != "" {
+		digest = shortDigest(entry.DigestAfter) + " (unchanged)"
+	}
+
+	lines := []string{
+		titleBorder(LEFT_HALF_CIRCLE) +
+			titleStyle.Render(fmt.Sprintf(" %s ", entry.Action)) +
+			titleBorder(RIGHT_HALF_CIRCLE),
+		wordwrap.String(entry.Model, width),
+		outcomeBorder(LEFT_HALF_CIRCLE) +
+			outcomeStyle(fmt.Sprintf(" %s ", entry.Outcome)) +
+			outcomeBorder(RIGHT_HALF_CIRCLE),
+		lipgloss.NewStyle().Foreground(dimTextColor).Render(
+			fmt.Sprintf(
+				"%s by %s on %s",
+				relTime(entry.Time),
+				entry.User,
+				entry.Host,
+			),
+		),
+		fmt.Sprintf(
+			"Took %s • %s transferred",
+			entry.Duration.Round(100*time.Millisecond),
+			humanize.Bytes(uint64(entry.Bytes)),
+		),
+		"Digest " + digest,
+	}
+	if entry.Source != "" {
+		lines = slices.Insert(lines, 2, lipgloss.NewStyle().Foreground(dimTextColor).Render("from "+entry.Source))
+	}
+	if entry.Error != "" {
+		lines = append(lines, wordwrap.String(entry.Error, width))
+	}
+
+	return strings.Join(lines, "\n\n")
+}
+
+func shortDigest(digest string) string {
+	if digest == "" {
+		return "none"
+	}
+
+	return digest[:min(12, len(digest))]
+}
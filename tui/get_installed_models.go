@@ -4,13 +4,31 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/gaurav-gosain/ollamanager/usage"
 	"github.com/ollama/ollama/api"
 )
 
 type InstalledOllamaModel api.ListModelResponse
 
+// ModelUsage is what ollamanager tracks about an installed model besides
+// what Ollama lists, see GetModelUsage.
+type ModelUsage struct {
+	// Pinned models are protected from deletion, see config.Config.Pinned.
+	Pinned bool
+	// LastUsed and Loaded are recorded by the usage sampler, LastUsed is zero
+	// for models never seen loaded.
+	LastUsed time.Time
+	Loaded   time.Duration
+	// Unused models were neither pulled nor loaded within config.UnusedAfter.
+	Unused bool
+}
+
 func GetInstalledModels() ([]InstalledOllamaModel, error) {
 	client, err := api.ClientFromEnvironment()
 	if err != nil {
@@ -32,16 +50,127 @@ func GetInstalledModels() ([]InstalledOllamaModel, error) {
 	return installedModels, nil
 }
 
-func (model InstalledOllamaModel) Title() string {
-	return model.Name
+// GetModelUsage returns the pins of the user config and the usage log records
+// of the models, keyed by model name.
+func GetModelUsage(models []InstalledOllamaModel) (map[string]ModelUsage, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var usageLog usage.Log
+	if path, err := usage.Path(); err == nil {
+		usageLog, _ = usage.Read(path)
+	}
+
+	modelUsage := make(map[string]ModelUsage, len(models))
+	for _, model := range models {
+		record := usageLog.Models[model.Name]
+		u := ModelUsage{
+			Pinned:   cfg.IsPinned(model.Name),
+			LastUsed: record.LastUsed,
+			Loaded:   record.Loaded,
+		}
+		u.Unused = Now().Sub(lastActive(model, u)) > cfg.UnusedAfter()
+		modelUsage[model.Name] = u
+	}
+
+	return modelUsage, nil
+}
+
+// lastActive returns when the model was last loaded or pulled, whichever is
+// later.
+func lastActive(model InstalledOllamaModel, u ModelUsage) time.Time {
+	if u.LastUsed.After(model.ModifiedAt) {
+		return u.LastUsed
+	}
+	return model.ModifiedAt
+}
+
+// IsEmbedding reports whether the model looks like an embedding model, from
+// its BERT-based family. It is only a guess for models the library does not
+// list, see installedItem.IsEmbedding.
+func (model InstalledOllamaModel) IsEmbedding() bool {
+	return slices.ContainsFunc(model.Details.Families, func(family string) bool {
+		return strings.Contains(family, "bert")
+	}) || strings.Contains(model.Details.Family, "bert")
 }
 
+func (model InstalledOllamaModel) Title() string { return model.Name }
+
 func (model InstalledOllamaModel) Description() string {
 	return fmt.Sprintf(
 		"%s • %s • %s",
 		humanize.Bytes(uint64(model.Size)),
 		model.Details.ParameterSize,
-		humanize.Time(model.ModifiedAt),
+		relTime(model.ModifiedAt),
 	)
 }
+
 func (model InstalledOllamaModel) FilterValue() string { return model.Name }
+
+// installedItem is an entry of the Manage tab list: an installed model along
+// with its usage.
+type installedItem struct {
+	InstalledOllamaModel
+	ModelUsage
+	// library is the library entry of the model, nil when the library was
+	// not loaded or does not list it.
+	library *OllamaModel
+}
+
+// IsEmbedding reports whether the model is an embedding model: tagged
+// "embedding" in the library when it lists the model, guessed from its family
+// otherwise.
+func (item installedItem) IsEmbedding() bool {
+	if item.library != nil {
+		return item.library.IsEmbedding()
+	}
+	return item.InstalledOllamaModel.IsEmbedding()
+}
+
+// libraryModel returns the library entry of an installed model, by its name
+// without the tag, or nil.
+func libraryModel(library []OllamaModel, name string) *OllamaModel {
+	name, _, _ = strings.Cut(name, ":")
+	for i := range library {
+		if library[i].Name == name {
+			return &library[i]
+		}
+	}
+	return nil
+}
+
+// LastActive returns when the model was last loaded or pulled, whichever is
+// later.
+func (item installedItem) LastActive() time.Time {
+	return lastActive(item.InstalledOllamaModel, item.ModelUsage)
+}
+
+func (item installedItem) Title() string {
+	if item.Pinned {
+		return item.Name + " " + LOCK
+	}
+	return item.Name
+}
+
+func (item installedItem) Description() string {
+	return item.InstalledOllamaModel.Description() + " • " + item.lastUsedText()
+}
+
+func (item installedItem) lastUsedText() string {
+	if item.LastUsed.IsZero() {
+		return "never used"
+	}
+	return "used " + relTime(item.LastUsed)
+}
+
+// loadedText formats the total loaded time to the minute.
+func (item installedItem) loadedText() string {
+	if item.Loaded < time.Minute {
+		return "loaded for under a minute"
+	}
+
+	loaded := item.Loaded.Round(time.Minute).String()
+	return "loaded for " + strings.TrimSuffix(loaded, "0s") + " in total"
+}
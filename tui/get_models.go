@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -12,14 +13,23 @@ import (
 )
 
 type OllamaModel struct {
-	Name      string
-	Desc      string
-	Pulls     string
-	Tags      string
-	Updated   string
+	Name    string
+	Desc    string
+	Pulls   string
+	Tags    string
+	Updated string
+	// ExtraInfo are the tags of the model on the library page, e.g. "tools",
+	// "embedding" or its sizes.
 	ExtraInfo []string
+	// Disallowed is why the policy does not allow pulling the model, empty
+	// when it does.
+	Disallowed string
 }
 
+// LibraryURL is the ollama.com library page installable models and their tags
+// are scraped from. It can be pointed at a fake library (see ollamatest).
+var LibraryURL = "https://ollama.com/library"
+
 func removeWhitespace(input string) string {
 	// Match any sequence of whitespace characters or newline characters
 	regex := regexp.MustCompile(`\s+`)
@@ -29,7 +39,7 @@ func removeWhitespace(input string) string {
 }
 
 func GetAvailableModels() ([]OllamaModel, error) {
-	resp, err := http.Get("https://ollama.com/library")
+	resp, err := http.Get(LibraryURL)
 	if err != nil {
 		return nil, err
 	}
@@ -102,24 +112,8 @@ func GetAvailableModels() ([]OllamaModel, error) {
 				Text(),
 		)
 
-		tagStyle := titleStyle.
-			Background(lipgloss.Color("242")).Render
-
-		tagBorder := titleStyle.Foreground(lipgloss.Color("242")).UnsetBackground().Render
-
 		root.Find("div > span").Each(func(i int, span *goquery.Selection) {
-			model.ExtraInfo = append(
-				model.ExtraInfo,
-				tagBorder(LEFT_HALF_CIRCLE)+
-					tagStyle(
-						fmt.Sprintf(
-							" %s ",
-							removeWhitespace(
-								span.Text(),
-							),
-						),
-					)+tagBorder(RIGHT_HALF_CIRCLE),
-			)
+			model.ExtraInfo = append(model.ExtraInfo, removeWhitespace(span.Text()))
 		})
 
 		models = append(models, model)
@@ -128,11 +122,28 @@ func GetAvailableModels() ([]OllamaModel, error) {
 	return models, nil
 }
 
+// IsEmbedding reports whether the library tags the model as an embedding
+// model.
+func (model OllamaModel) IsEmbedding() bool {
+	return slices.Contains(model.ExtraInfo, "embedding")
+}
+
+// renderTag renders a library tag as a pill.
+func renderTag(tag string) string {
+	tagStyle := titleStyle.Background(lipgloss.Color("242")).Render
+	tagBorder := titleStyle.Foreground(lipgloss.Color("242")).UnsetBackground().Render
+
+	return tagBorder(LEFT_HALF_CIRCLE) + tagStyle(fmt.Sprintf(" %s ", tag)) + tagBorder(RIGHT_HALF_CIRCLE)
+}
+
 func (model OllamaModel) Title() string {
 	return model.Name
 }
 
 func (model OllamaModel) Description() string {
+	if model.Disallowed != "" {
+		return "Blocked: " + model.Disallowed
+	}
 	return fmt.Sprintf(
 		"↓ %s • %s tags • %s",
 		model.Pulls, model.Tags, model.Updated,
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/ollamatest"
+	"github.com/ollama/ollama/api"
+)
+
+func newTestServer(t *testing.T) *ollamatest.Server {
+	t.Helper()
+
+	srv := ollamatest.NewServer()
+	t.Cleanup(srv.Close)
+
+	t.Setenv("OLLAMA_HOST", srv.URL)
+	t.Setenv("OLLAMANAGER_CONFIG_DIR", t.TempDir())
+	t.Setenv("OLLAMANAGER_DATA_DIR", t.TempDir())
+
+	return srv
+}
+
+func TestGetAvailableModels(t *testing.T) {
+	srv := newTestServer(t)
+	srv.AddLibraryModel(ollamatest.LibraryModel{
+		Name:        "llama3.2",
+		Description: "Meta's Llama 3.2 goes small with 1B and 3B models.",
+		Pulls:       "5.1M",
+		Updated:     "2 weeks ago",
+		Capability:  []string{"tools"},
+		Tags:        []string{"latest", "1b", "3b"},
+	})
+
+	library := LibraryURL
+	LibraryURL = srv.LibraryURL()
+	t.Cleanup(func() { LibraryURL = library })
+
+	models, err := GetAvailableModels()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(models) != 1 {
+		t.Fatalf("models = %+v", models)
+	}
+	model := models[0]
+	if model.Name != "llama3.2" || model.Pulls != "5.1M" || model.Tags != "3" {
+		t.Fatalf("model = %+v", model)
+	}
+	if !slices.Contains(model.ExtraInfo, "tools") {
+		t.Fatalf("extra info = %q, want the plain library tags", model.ExtraInfo)
+	}
+}
+
+func TestInstalledIsEmbedding(t *testing.T) {
+	library := []OllamaModel{
+		{Name: "nomic-embed-text", ExtraInfo: []string{"embedding"}},
+		{Name: "gte-qwen", ExtraInfo: []string{"embedding", "1.5b"}},
+		{Name: "bert-chat", ExtraInfo: []string{"tools"}},
+	}
+
+	tests := []struct {
+		name   string
+		family string
+		want   bool
+	}{
+		// tagged by the library, whatever the family
+		{"nomic-embed-text:latest", "nomic-bert", true},
+		{"gte-qwen:1.5b", "qwen2", true},
+		{"bert-chat:latest", "bert", false},
+		// not listed, guessed from the family
+		{"all-minilm:latest", "bert", true},
+		{"llama3.2:latest", "llama", false},
+	}
+
+	for _, tt := range tests {
+		item := installedItem{
+			InstalledOllamaModel: InstalledOllamaModel{Name: tt.name, Details: api.ModelDetails{Family: tt.family}},
+			library:              libraryModel(library, tt.name),
+		}
+		if got := item.IsEmbedding(); got != tt.want {
+			t.Errorf("%s IsEmbedding() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetRunningModels(t *testing.T) {
+	srv := newTestServer(t)
+	srv.AddModel(ollamatest.Model("llama3.2", 2<<30))
+	srv.AddModel(ollamatest.Model("qwen2.5:7b", 4<<30))
+	if err := srv.LoadModel("qwen2.5:7b", 4<<30, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	running, err := GetRunningModels()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(running) != 1 || running[0].Title() != "qwen2.5:7b" || running[0].SizeVRAM != 4<<30 {
+		t.Fatalf("running = %+v", running)
+	}
+}
+
+func TestGetInstalledModels(t *testing.T) {
+	srv := newTestServer(t)
+	srv.AddModel(ollamatest.Model("llama3.2", 2<<30))
+
+	installed, err := GetInstalledModels()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(installed) != 1 || installed[0].Name != "llama3.2:latest" {
+		t.Fatalf("installed = %+v", installed)
+	}
+
+	modelUsage, err := GetModelUsage(installed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, ok := modelUsage["llama3.2:latest"]; !ok || u.Pinned || u.Unused {
+		t.Fatalf("usage = %+v", modelUsage)
+	}
+}
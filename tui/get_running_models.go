@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log"
 
-	humanize "github.com/dustin/go-humanize"
 	"github.com/ollama/ollama/api"
 )
 
@@ -40,7 +39,7 @@ func (model RunningOllamaModel) Description() string {
 	return fmt.Sprintf(
 		"%s • %s",
 		model.Details.ParameterSize,
-		humanize.Time(model.ExpiresAt),
+		relTime(model.ExpiresAt),
 	)
 }
 func (model RunningOllamaModel) FilterValue() string { return model.Name }
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/session"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+type SessionEntry struct {
+	session.Session
+}
+
+func GetSessions() ([]SessionEntry, error) {
+	sessions, err := session.List()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]SessionEntry, len(sessions))
+	for i, s := range sessions {
+		entries[i] = SessionEntry{Session: s}
+	}
+
+	return entries, nil
+}
+
+func (entry SessionEntry) Title() string {
+	return entry.Name
+}
+
+func (entry SessionEntry) Description() string {
+	return fmt.Sprintf(
+		"%s • %d messages • updated %s",
+		entry.Model,
+		len(entry.Messages),
+		relTime(entry.UpdatedAt),
+	)
+}
+
+// FilterValue matches on the session name and the model.
+func (entry SessionEntry) FilterValue() string { return entry.Name + " " + entry.Model }
+
+// sessionInfo renders the info pane for a chat session, with the start of the
+// last message.
+func sessionInfo(entry SessionEntry, width int) string {
+	lines := []string{
+		titleBorder(LEFT_HALF_CIRCLE) +
+			titleStyle.Render(fmt.Sprintf(" %s ", entry.Name)) +
+			titleBorder(RIGHT_HALF_CIRCLE),
+		tagBorder(LEFT_HALF_CIRCLE) +
+			tagStyle(fmt.Sprintf(" %s ", entry.Model)) +
+			tagBorder(RIGHT_HALF_CIRCLE),
+		lipgloss.NewStyle().Foreground(dimTextColor).Render(
+			fmt.Sprintf(
+				"Started %s • updated %s",
+				relTime(entry.CreatedAt),
+				relTime(entry.UpdatedAt),
+			),
+		),
+	}
+
+	if options := entry.OptionsText(); options != "" {
+		lines = append(lines, wordwrap.String(options, width))
+	}
+	if entry.System != "" {
+		lines = append(lines, wordwrap.String("System: "+truncateText(entry.System, 200), width))
+	}
+	if n := len(entry.Messages); n > 0 {
+		last := entry.Messages[n-1]
+		lines = append(lines, wordwrap.String(last.Role+": "+truncateText(last.Content, 300), width))
+	}
+
+	lines = append(lines, lipgloss.NewStyle().Foreground(dimTextColor).Render("enter to resume, rename, export or delete"))
+
+	return strings.Join(lines, "\n\n")
+}
+
+// truncateText shortens s to n runes on a single line.
+func truncateText(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
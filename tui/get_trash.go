@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/gaurav-gosain/ollamanager/store"
+)
+
+type TrashEntry struct {
+	store.TrashItem
+	// PurgeAt is when the model is permanently deleted, zero if soft
+	// deletion has since been disabled.
+	PurgeAt time.Time
+}
+
+func GetTrash() ([]TrashEntry, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := store.Open("").TrashItems()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]TrashEntry, len(items))
+	for i, item := range items {
+		entries[i] = TrashEntry{TrashItem: item}
+		if retention := cfg.TrashRetention(); retention > 0 {
+			entries[i].PurgeAt = item.DeletedAt.Add(retention)
+		}
+	}
+
+	return entries, nil
+}
+
+func (entry TrashEntry) Title() string {
+	return entry.Model
+}
+
+func (entry TrashEntry) Description() string {
+	return fmt.Sprintf(
+		"deleted %s • %s",
+		relTime(entry.DeletedAt),
+		humanize.Bytes(uint64(entry.Size)),
+	)
+}
+
+func (entry TrashEntry) FilterValue() string { return entry.Model }
+
+// trashInfo renders the info pane for a trashed model.
+func trashInfo(entry TrashEntry) string {
+	purge := "Kept until purged"
+	if !entry.PurgeAt.IsZero() {
+		purge = "Purged " + relTime(entry.PurgeAt)
+	}
+
+	return strings.Join([]string{
+		titleBorder(LEFT_HALF_CIRCLE) +
+			titleStyle.Render(fmt.Sprintf(" %s ", entry.Model)) +
+			titleBorder(RIGHT_HALF_CIRCLE),
+		lipgloss.NewStyle().Foreground(dimTextColor).Render(
+			"Deleted " + relTime(entry.DeletedAt),
+		),
+		fmt.Sprintf(
+			"%s in trash • %s shared with other models",
+			humanize.Bytes(uint64(entry.Freed)),
+			humanize.Bytes(uint64(entry.Size-entry.Freed)),
+		),
+		tagBorder(LEFT_HALF_CIRCLE) +
+			tagStyle(fmt.Sprintf(" %s ", purge)) +
+			tagBorder(RIGHT_HALF_CIRCLE),
+		lipgloss.NewStyle().Foreground(dimTextColor).Render("enter to restore or purge"),
+	}, "\n\n")
+}
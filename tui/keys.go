@@ -1,19 +1,56 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/v2/key"
+import (
+	"github.com/charmbracelet/bubbles/v2/key"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+)
+
+// ActionKeys maps every manage action to the key selecting it on the Manage
+// tab.
+var ActionKeys = map[tabs.ManageAction]string{
+	tabs.CHAT:   "c",
+	tabs.UPDATE: "u",
+	tabs.DELETE: "d",
+	tabs.CREATE: "m",
+	tabs.IMPORT: "i",
+	tabs.COPY:   "y",
+	tabs.PUSH:   "s",
+	tabs.PIN:    "t",
+
+	tabs.BENCHMARK: "b",
+	tabs.COMPARE:   "v",
+	tabs.EMBED:     "e",
+	tabs.LOAD:      "r",
+	tabs.TOOLS:     "f",
+	tabs.SCHEMA:    "g",
+	tabs.TEMPLATE:  "w",
+}
+
+func actionForKey(keypress string) (tabs.ManageAction, bool) {
+	for action, k := range ActionKeys {
+		if k == keypress {
+			return action, true
+		}
+	}
+
+	return "", false
+}
 
 type KeyMap struct {
-	Up           key.Binding
-	Down         key.Binding
-	Left         key.Binding
-	Right        key.Binding
-	Quit         key.Binding
-	Enter        key.Binding
-	Filter       key.Binding
-	ClearFilter  key.Binding
-	NextTab      key.Binding
-	PrevTab      key.Binding
-	FullHelpKeys [][]key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	Left        key.Binding
+	Right       key.Binding
+	Quit        key.Binding
+	Enter       key.Binding
+	Filter      key.Binding
+	ClearFilter key.Binding
+	NextTab     key.Binding
+	PrevTab     key.Binding
+	// SortInstalled and FilterUnused only apply to the Manage tab.
+	SortInstalled key.Binding
+	FilterUnused  key.Binding
+	FullHelpKeys  [][]key.Binding
 }
 
 var Keys = KeyMap{
@@ -53,6 +90,14 @@ var Keys = KeyMap{
 		key.WithKeys("p", "shift+tab"),
 		key.WithHelp("p/shift+tab", "switch to the previous tab"),
 	),
+	SortInstalled: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "sort by modified/last used/loaded time/size"),
+	),
+	FilterUnused: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "show only unused models"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "esc", "ctrl+c"),
 		key.WithHelp("q/ctrl+c", "quit"),
@@ -14,23 +14,126 @@ import (
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/charmbracelet/lipgloss/v2/compat"
 	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/timeline"
 )
 
+// NewModelSelector builds the tabbed model selector from already fetched
+// models. ModelPicker uses it after loading the models from Ollama, tests can
+// use it to render the selector with fixed data. modelUsage holds the pins
+// and usage of the installed models by name, see GetModelUsage.
+func NewModelSelector(
+	selectedTabs []tabs.Tab,
+	approvedActions []tabs.ManageAction,
+	models []OllamaModel,
+	installedModels []InstalledOllamaModel,
+	modelUsage map[string]ModelUsage,
+	runningModels []RunningOllamaModel,
+	historyEntries []HistoryEntry,
+	trashEntries []TrashEntry,
+	sessionEntries []SessionEntry,
+) ModelSelector {
+	var installableItems, installedItems, runningItems, historyItems, trashItems, sessionItems []list.Item
+
+	for _, model := range models {
+		installableItems = append(installableItems, list.Item(model))
+	}
+
+	installableModelsList := list.New(installableItems, newInstallableDelegate(), 0, 0)
+	installableModelsList.Title = "Pick a Model to install..."
+	installableModelsList.SetShowHelp(false)
+
+	installed := make([]installedItem, len(installedModels))
+	for i, model := range installedModels {
+		installed[i] = installedItem{
+			InstalledOllamaModel: model,
+			ModelUsage:           modelUsage[model.Name],
+			library:              libraryModel(models, model.Name),
+		}
+		installedItems = append(installedItems, list.Item(installed[i]))
+	}
+
+	installedModelsList := list.New(installedItems, list.NewDefaultDelegate(), 0, 0)
+	installedModelsList.Title = installedTitle
+	installedModelsList.SetShowHelp(false)
+
+	for _, model := range runningModels {
+		runningItems = append(runningItems, list.Item(model))
+	}
+
+	runningModelsList := list.New(runningItems, list.NewDefaultDelegate(), 0, 0)
+	runningModelsList.Title = "Pick a running Model..."
+	runningModelsList.SetShowHelp(false)
+
+	for _, entry := range historyEntries {
+		historyItems = append(historyItems, list.Item(entry))
+	}
+
+	historyList := list.New(historyItems, list.NewDefaultDelegate(), 0, 0)
+	historyList.Title = "Action history"
+	historyList.SetShowHelp(false)
+
+	for _, entry := range trashEntries {
+		trashItems = append(trashItems, list.Item(entry))
+	}
+
+	trashList := list.New(trashItems, list.NewDefaultDelegate(), 0, 0)
+	trashList.Title = "Pick a deleted Model..."
+	trashList.SetShowHelp(false)
+
+	for _, entry := range sessionEntries {
+		sessionItems = append(sessionItems, list.Item(entry))
+	}
+
+	sessionList := list.New(sessionItems, list.NewDefaultDelegate(), 0, 0)
+	sessionList.Title = "Pick a chat session..."
+	sessionList.SetShowHelp(false)
+
+	helpModel := help.New()
+	helpModel.ShowAll = true
+	helpModel.Styles.FullDesc.UnsetForeground()
+	helpModel.Styles.FullKey = lipgloss.NewStyle().Foreground(compat.AdaptiveColor{Light: lipgloss.Color("#43BF6D"), Dark: lipgloss.Color("#73F59F")})
+
+	return ModelSelector{
+		installableList: installableModelsList,
+		installedList:   installedModelsList,
+		runningList:     runningModelsList,
+		historyList:     historyList,
+		trashList:       trashList,
+		sessionList:     sessionList,
+		installedModels: installed,
+		installedOrder:  ORDER_MODIFIED,
+		Tabs:            selectedTabs,
+		ApprovedActions: approvedActions,
+		help:            helpModel,
+	}
+}
+
+// PickerOption configures the selector shown by ModelPicker.
+type PickerOption func(*ModelSelector)
+
+// WithTimeline shows the loaded models of ring over time in the Monitor tab.
+func WithTimeline(ring *timeline.Ring) PickerOption {
+	return func(m *ModelSelector) {
+		m.Timeline = ring
+	}
+}
+
 func ModelPicker(
 	selectedTabs []tabs.Tab,
 	approvedActions []tabs.ManageAction,
+	opts ...PickerOption,
 ) (result ModelSelector, err error) {
 	ctx := context.Background()
 
 	var spinnerErr error
 
-	var installableItems, installedItems, runningItems []list.Item
-
-	var installableModelsList, installedModelsList, runningModelsList list.Model
-
 	var models []OllamaModel
 	var installedModels []InstalledOllamaModel
+	var modelUsage map[string]ModelUsage
 	var runningModels []RunningOllamaModel
+	var historyEntries []HistoryEntry
+	var trashEntries []TrashEntry
+	var sessionEntries []SessionEntry
 
 	var loadModels func()
 
@@ -43,6 +146,9 @@ func ModelPicker(
 	if hasInstallTab {
 		loadModels = func() {
 			models, err = GetAvailableModels()
+			if err == nil {
+				err = applyPolicy(models)
+			}
 			ctx.Done() // signal that model fetching is done
 		}
 		spinnerErr = spinner.
@@ -58,18 +164,13 @@ func ModelPicker(
 		if err != nil {
 			return
 		}
-
-		for _, model := range models {
-			installableItems = append(installableItems, list.Item(model))
-		}
-
-		installableModelsList = list.New(installableItems, list.NewDefaultDelegate(), 0, 0)
-		installableModelsList.Title = "Pick a Model to install..."
-		installableModelsList.SetShowHelp(false)
 	}
 
 	loadModels = func() {
 		installedModels, err = GetInstalledModels()
+		if err == nil {
+			modelUsage, err = GetModelUsage(installedModels)
+		}
 		ctx.Done() // signal that model fetching is done
 	}
 
@@ -87,14 +188,6 @@ func ModelPicker(
 		return
 	}
 
-	for _, model := range installedModels {
-		installedItems = append(installedItems, list.Item(model))
-	}
-
-	installedModelsList = list.New(installedItems, list.NewDefaultDelegate(), 0, 0)
-	installedModelsList.Title = "Pick an installed Model..."
-	installedModelsList.SetShowHelp(false)
-
 	loadModels = func() {
 		runningModels, err = GetRunningModels()
 		ctx.Done() // signal that model fetching is done
@@ -114,26 +207,40 @@ func ModelPicker(
 	// 	return
 	// }
 
-	for _, model := range runningModels {
-		runningItems = append(runningItems, list.Item(model))
+	if slices.Contains(selectedTabs, tabs.HISTORY) {
+		historyEntries, err = GetHistory()
+		if err != nil {
+			return
+		}
 	}
 
-	runningModelsList = list.New(runningItems, list.NewDefaultDelegate(), 0, 0)
-	runningModelsList.Title = "Pick a running Model..."
-	runningModelsList.SetShowHelp(false)
+	if slices.Contains(selectedTabs, tabs.TRASH) {
+		trashEntries, err = GetTrash()
+		if err != nil {
+			return
+		}
+	}
 
-	helpModel := help.New()
-	helpModel.ShowAll = true
-	helpModel.Styles.FullDesc.UnsetForeground()
-	helpModel.Styles.FullKey = lipgloss.NewStyle().Foreground(compat.AdaptiveColor{Light: lipgloss.Color("#43BF6D"), Dark: lipgloss.Color("#73F59F")})
+	if slices.Contains(selectedTabs, tabs.SESSIONS) {
+		sessionEntries, err = GetSessions()
+		if err != nil {
+			return
+		}
+	}
 
-	m := ModelSelector{
-		installableList: installableModelsList,
-		installedList:   installedModelsList,
-		runningList:     runningModelsList,
-		Tabs:            selectedTabs,
-		ApprovedActions: approvedActions,
-		help:            helpModel,
+	m := NewModelSelector(
+		selectedTabs,
+		approvedActions,
+		models,
+		installedModels,
+		modelUsage,
+		runningModels,
+		historyEntries,
+		trashEntries,
+		sessionEntries,
+	)
+	for _, opt := range opts {
+		opt(&m)
 	}
 
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithFerociousRenderer())
@@ -147,9 +254,12 @@ func ModelPicker(
 
 	// TODO: could be cleaner
 	if err != nil ||
-		(model.SelectedInstallableModel.Name == "" &&
+		(model.ManageAction != tabs.IMPORT &&
+			model.SelectedInstallableModel.Name == "" &&
 			model.SelectedInstalledModel.Name == "" &&
-			model.SelectedRunningModel.Name == "") {
+			model.SelectedRunningModel.Name == "" &&
+			model.SelectedTrashEntry.ID == "" &&
+			model.SelectedSession.ID == "") {
 		err = errors.New(`failed to pick a model :(`)
 		return
 	}
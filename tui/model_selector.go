@@ -13,6 +13,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/timeline"
 	"github.com/muesli/reflow/wordwrap"
 )
 
@@ -62,14 +63,23 @@ type ModelSelector struct {
 	installableList          list.Model
 	installedList            list.Model
 	runningList              list.Model
+	historyList              list.Model
+	trashList                list.Model
+	sessionList              list.Model
+	installedModels          []installedItem
+	installedOrder           InstalledOrder
+	unusedOnly               bool
 	help                     help.Model
 	SelectedInstallableModel OllamaModel
 	SelectedRunningModel     RunningOllamaModel
 	SelectedInstalledModel   InstalledOllamaModel
+	SelectedTrashEntry       TrashEntry
+	SelectedSession          SessionEntry
 	Action                   tabs.Tab
 	ManageAction             tabs.ManageAction
 	Tabs                     []tabs.Tab
 	ApprovedActions          []tabs.ManageAction
+	Timeline                 *timeline.Ring
 	width                    int
 	height                   int
 	ActiveTab                int
@@ -78,19 +88,31 @@ type ModelSelector struct {
 }
 
 func (m ModelSelector) Init() (tea.Model, tea.Cmd) {
+	if m.Timeline != nil && slices.Contains(m.Tabs, tabs.MONITOR) {
+		return m, timelineTick()
+	}
 	return m, nil
 }
 
-func (m *ModelSelector) SetSelectedModel(installAction, manageAction, monitorAction bool) {
+func (m *ModelSelector) SetSelectedModel(installAction, manageAction, monitorAction, trashAction, sessionAction bool) {
 	if installAction {
 		m.Action = tabs.INSTALL
 		m.SelectedInstallableModel = m.installableList.SelectedItem().(OllamaModel)
 	} else if monitorAction {
 		m.Action = tabs.MONITOR
 		m.SelectedRunningModel = m.runningList.SelectedItem().(RunningOllamaModel)
+	} else if trashAction {
+		m.Action = tabs.TRASH
+		m.SelectedTrashEntry = m.trashList.SelectedItem().(TrashEntry)
+	} else if sessionAction {
+		m.Action = tabs.SESSIONS
+		m.SelectedSession = m.sessionList.SelectedItem().(SessionEntry)
 	} else if manageAction {
 		m.Action = tabs.MANAGE
-		m.SelectedInstalledModel = m.installedList.SelectedItem().(InstalledOllamaModel)
+		// importing doesn't need a selected model, the list may even be empty
+		if item, ok := m.installedList.SelectedItem().(installedItem); ok {
+			m.SelectedInstalledModel = item.InstalledOllamaModel
+		}
 	}
 }
 
@@ -98,10 +120,20 @@ func (m ModelSelector) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	installAction := m.Tabs[m.ActiveTab] == tabs.INSTALL
 	manageAction := m.Tabs[m.ActiveTab] == tabs.MANAGE
 	monitorAction := m.Tabs[m.ActiveTab] == tabs.MONITOR
+	historyAction := m.Tabs[m.ActiveTab] == tabs.HISTORY
+	trashAction := m.Tabs[m.ActiveTab] == tabs.TRASH
+	sessionAction := m.Tabs[m.ActiveTab] == tabs.SESSIONS
 
 	switch msg := msg.(type) {
+	case timelineTickMsg:
+		// redraw with the latest samples
+		return m, timelineTick()
 	case tea.KeyMsg:
-		if m.installableList.FilterState() == list.Filtering || m.installedList.FilterState() == list.Filtering {
+		if m.installableList.FilterState() == list.Filtering ||
+			m.installedList.FilterState() == list.Filtering ||
+			m.historyList.FilterState() == list.Filtering ||
+			m.trashList.FilterState() == list.Filtering ||
+			m.sessionList.FilterState() == list.Filtering {
 			break
 		}
 
@@ -130,30 +162,40 @@ func (m ModelSelector) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.ActiveTab = max(m.ActiveTab-1, 0)
 			m.Action = tabs.Tab(m.Tabs[m.ActiveTab])
 			return m, nil
-		case "u":
-			// if on manage tab, select the update `ManageAction` (if it is in the list of approved actions)
-			if manageAction && slices.Contains(m.ApprovedActions, tabs.UPDATE) {
-				m.ManageAction = tabs.UPDATE
-				m.SetSelectedModel(installAction, manageAction, monitorAction)
-				return m, tea.Quit
+		case "o":
+			if manageAction {
+				m.installedOrder = nextOrder(m.installedOrder)
+				return m, m.refreshInstalled()
 			}
-		case "d":
-			// if on manage tab, select the delete `ManageAction` (if it is in the list of approved actions)
-			if manageAction && slices.Contains(m.ApprovedActions, tabs.DELETE) {
-				m.ManageAction = tabs.DELETE
-				m.SetSelectedModel(installAction, manageAction, monitorAction)
-				return m, tea.Quit
-			}
-		case "c":
-			// if on manage tab, select the chat `ManageAction` (if it is in the list of approved actions)
-			if manageAction && slices.Contains(m.ApprovedActions, tabs.CHAT) {
-				m.ManageAction = tabs.CHAT
-				m.SetSelectedModel(installAction, manageAction, monitorAction)
-				return m, tea.Quit
+		case "x":
+			if manageAction {
+				m.unusedOnly = !m.unusedOnly
+				return m, m.refreshInstalled()
 			}
 		case "enter":
-			m.SetSelectedModel(installAction, manageAction, monitorAction)
+			// history entries are only browsed, there is nothing to pick
+			if historyAction {
+				break
+			}
+			if trashAction && m.trashList.SelectedItem() == nil {
+				break
+			}
+			if sessionAction && m.sessionList.SelectedItem() == nil {
+				break
+			}
+			// models blocked by the policy are shown but can't be picked
+			if model, ok := m.installableList.SelectedItem().(OllamaModel); installAction && ok && model.Disallowed != "" {
+				break
+			}
+			m.SetSelectedModel(installAction, manageAction, monitorAction, trashAction, sessionAction)
 			return m, tea.Quit
+		default:
+			// if on manage tab, select the `ManageAction` bound to the key (if it is in the list of approved actions)
+			if action, ok := actionForKey(keypress); ok && manageAction && slices.Contains(m.ApprovedActions, action) {
+				m.ManageAction = action
+				m.SetSelectedModel(installAction, manageAction, monitorAction, trashAction, sessionAction)
+				return m, tea.Quit
+			}
 		}
 	case tea.WindowSizeMsg:
 		h, v := docStyle.GetFrameSize()
@@ -177,6 +219,15 @@ func (m ModelSelector) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if slices.Contains(m.Tabs, tabs.MANAGE) {
 			m.installedList.SetSize(listWidth, m.height-v)
 		}
+		if slices.Contains(m.Tabs, tabs.HISTORY) {
+			m.historyList.SetSize(listWidth, m.height-v)
+		}
+		if slices.Contains(m.Tabs, tabs.TRASH) {
+			m.trashList.SetSize(listWidth, m.height-v)
+		}
+		if slices.Contains(m.Tabs, tabs.SESSIONS) {
+			m.sessionList.SetSize(listWidth, m.height-v)
+		}
 	}
 
 	var cmd tea.Cmd
@@ -186,6 +237,12 @@ func (m ModelSelector) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.installableList, cmd = m.installableList.Update(msg)
 		} else if monitorAction {
 			m.runningList, cmd = m.runningList.Update(msg)
+		} else if historyAction {
+			m.historyList, cmd = m.historyList.Update(msg)
+		} else if trashAction {
+			m.trashList, cmd = m.trashList.Update(msg)
+		} else if sessionAction {
+			m.sessionList, cmd = m.sessionList.Update(msg)
 		} else {
 			m.installedList, cmd = m.installedList.Update(msg)
 		}
@@ -244,6 +301,12 @@ func (m ModelSelector) View() string {
 		list = m.runningList
 	case tabs.MANAGE:
 		list = m.installedList
+	case tabs.HISTORY:
+		list = m.historyList
+	case tabs.TRASH:
+		list = m.trashList
+	case tabs.SESSIONS:
+		list = m.sessionList
 	default:
 		list = m.installedList
 	}
@@ -272,7 +335,8 @@ func (m ModelSelector) View() string {
 				extraInfo := ""
 				if len(selectedModel.ExtraInfo) > 0 {
 					extraInfo = "\n\n"
-					for _, i := range selectedModel.ExtraInfo {
+					for _, tag := range selectedModel.ExtraInfo {
+						i := renderTag(tag)
 						if lipgloss.Width(extraInfo+i) >= m.width-list.Width()-8 {
 							extraInfo += "\n\n"
 						}
@@ -294,6 +358,12 @@ func (m ModelSelector) View() string {
 						),
 					),
 				)
+				if selectedModel.Disallowed != "" {
+					info += "\n\n" + tagBorder(LEFT_HALF_CIRCLE) +
+						tagStyle(" Blocked by policy ") +
+						tagBorder(RIGHT_HALF_CIRCLE) +
+						"\n\n" + wordwrap.String(selectedModel.Disallowed, m.width-list.Width()-8)
+				}
 			}
 		case tabs.MONITOR:
 			if selectedItem != nil {
@@ -322,7 +392,7 @@ func (m ModelSelector) View() string {
 							Render(
 								fmt.Sprintf(
 									" Expires in %s ",
-									humanize.Time(selectedModel.ExpiresAt),
+									relTime(selectedModel.ExpiresAt),
 								),
 							)+
 						titleBorder(RIGHT_HALF_CIRCLE),
@@ -334,9 +404,31 @@ func (m ModelSelector) View() string {
 					),
 				)
 			}
+			if m.Timeline != nil {
+				var selectedName string
+				if selectedItem != nil {
+					selectedName = selectedItem.(RunningOllamaModel).Name
+					info += "\n\n"
+				} else {
+					info = ""
+				}
+				info += timelineInfo(m.Timeline, selectedName, m.width-list.Width()-8)
+			}
+		case tabs.TRASH:
+			if selectedItem != nil {
+				info = trashInfo(selectedItem.(TrashEntry))
+			}
+		case tabs.SESSIONS:
+			if selectedItem != nil {
+				info = sessionInfo(selectedItem.(SessionEntry), m.width-list.Width()-8)
+			}
+		case tabs.HISTORY:
+			if selectedItem != nil {
+				info = historyInfo(selectedItem.(HistoryEntry), m.width-list.Width()-8)
+			}
 		default:
 			if selectedItem != nil {
-				selectedModel := selectedItem.(InstalledOllamaModel)
+				selectedModel := selectedItem.(installedItem)
 
 				isMultiModal := ""
 				if len(selectedModel.Details.Families) > 1 {
@@ -346,23 +438,50 @@ func (m ModelSelector) View() string {
 						titleBorder(RIGHT_HALF_CIRCLE)
 				}
 
+				tags := []string{
+					tagBorder(LEFT_HALF_CIRCLE) +
+						tagStyle(fmt.Sprintf(" %s ", selectedModel.Details.Format)) +
+						tagBorder(RIGHT_HALF_CIRCLE),
+					tagBorder(LEFT_HALF_CIRCLE) +
+						tagStyle(fmt.Sprintf(" %s ", selectedModel.Details.QuantizationLevel)) +
+						tagBorder(RIGHT_HALF_CIRCLE),
+				}
+				if selectedModel.Pinned {
+					tags = append(tags, tagBorder(LEFT_HALF_CIRCLE)+
+						tagStyle(fmt.Sprintf(" %s pinned ", LOCK))+
+						tagBorder(RIGHT_HALF_CIRCLE))
+				}
+				if selectedModel.IsEmbedding() {
+					tags = append(tags, tagBorder(LEFT_HALF_CIRCLE)+
+						tagStyle(" embedding ")+
+						tagBorder(RIGHT_HALF_CIRCLE))
+				}
+				if selectedModel.Unused {
+					tags = append(tags, tagBorder(LEFT_HALF_CIRCLE)+
+						tagStyle(" unused ")+
+						tagBorder(RIGHT_HALF_CIRCLE))
+				}
+
+				usageText := "Never seen loaded"
+				if !selectedModel.LastUsed.IsZero() {
+					usageText = fmt.Sprintf(
+						"Last used %s • %s",
+						relTime(selectedModel.LastUsed),
+						selectedModel.loadedText(),
+					)
+				}
+
 				info = fmt.Sprintf(
-					"%s\n\n%s\n\n%s\n\n%s\n\n%s",
+					"%s\n\n%s\n\n%s\n\n%s\n\n%s\n\n%s",
 					titleBorder(LEFT_HALF_CIRCLE)+
 						titleStyle.Render(fmt.Sprintf(" %s ", selectedModel.Name))+
 						titleBorder(RIGHT_HALF_CIRCLE),
-					strings.Join([]string{
-						tagBorder(LEFT_HALF_CIRCLE) +
-							tagStyle(fmt.Sprintf(" %s ", selectedModel.Details.Format)) +
-							tagBorder(RIGHT_HALF_CIRCLE),
-						tagBorder(LEFT_HALF_CIRCLE) +
-							tagStyle(fmt.Sprintf(" %s ", selectedModel.Details.QuantizationLevel)) +
-							tagBorder(RIGHT_HALF_CIRCLE),
-					}, " "),
+					strings.Join(tags, " "),
 					wordwrap.String(selectedModel.Digest, m.width-list.Width()),
 					lipgloss.NewStyle().Foreground(dimTextColor).Render(
 						selectedModel.Description(),
 					),
+					usageText,
 					isMultiModal,
 				)
 			}
@@ -395,13 +514,14 @@ func (m ModelSelector) View() string {
 			keyMap := defaultKeys
 			for _, action := range m.ApprovedActions {
 
-				keyBind := string(strings.ToLower(string(action))[0])
+				keyBind := ActionKeys[action]
 
 				keyMap[0] = append(keyMap[0], key.NewBinding(
 					key.WithKeys(keyBind),
 					key.WithHelp(keyBind, string(action)),
 				))
 			}
+			keyMap[1] = append(keyMap[1], Keys.SortInstalled, Keys.FilterUnused)
 			Keys.SetFullHelpKeys(keyMap)
 		} else {
 			Keys.SetFullHelpKeys(defaultKeys)
@@ -0,0 +1,86 @@
+package tui_test
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/gaurav-gosain/ollamanager/tabs"
+	"github.com/gaurav-gosain/ollamanager/timeline"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/gaurav-gosain/ollamanager/tui/tuitest"
+)
+
+var (
+	selectorTabs = []tabs.Tab{
+		tabs.INSTALL,
+		tabs.MANAGE,
+		tabs.MONITOR,
+		tabs.HISTORY,
+	}
+	selectorActions = []tabs.ManageAction{
+		tabs.UPDATE,
+		tabs.DELETE,
+		tabs.COPY,
+		tabs.PIN,
+		tabs.CHAT,
+	}
+)
+
+func newSelector() tea.Model {
+	return tui.NewModelSelector(
+		selectorTabs,
+		selectorActions,
+		tuitest.Installable(),
+		tuitest.Installed(),
+		tuitest.Usage(),
+		tuitest.Running(),
+		tuitest.History(),
+		nil,
+		nil,
+	)
+}
+
+// testTimeline returns a ring with llama3.2 loaded for the last ten minutes.
+func testTimeline() *timeline.Ring {
+	ring := timeline.NewRing(15*time.Minute, 30*time.Second)
+	for t := tuitest.Now.Add(-10 * time.Minute); !t.After(tuitest.Now); t = t.Add(30 * time.Second) {
+		ring.Add(timeline.Sample{
+			Time:   t,
+			Models: []timeline.Model{{Name: "llama3.2:latest", Size: 3077459968, SizeVRAM: 3077459968}},
+		})
+	}
+
+	return ring
+}
+
+func TestModelSelectorGolden(t *testing.T) {
+	restore := tuitest.Deterministic()
+	defer restore()
+
+	tests := []struct {
+		name string
+		msgs []tea.Msg
+	}{
+		{name: "install"},
+		{name: "manage", msgs: tuitest.Keys("n")},
+		{name: "monitor", msgs: tuitest.Keys("nn")},
+		{name: "history", msgs: tuitest.Keys("nnn")},
+		{name: "help", msgs: tuitest.Keys("n?")},
+	}
+
+	for _, tt := range tests {
+		tuitest.GoldenSizes(t, "selector_"+tt.name, newSelector, tt.msgs...)
+	}
+}
+
+func TestModelSelectorTimelineGolden(t *testing.T) {
+	restore := tuitest.Deterministic()
+	defer restore()
+
+	tuitest.GoldenSizes(t, "selector_timeline", func() tea.Model {
+		m := newSelector().(tui.ModelSelector)
+		tui.WithTimeline(testTimeline())(&m)
+		return m
+	}, tuitest.Keys("nn")...)
+}
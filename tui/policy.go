@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"io"
+
+	"github.com/charmbracelet/bubbles/v2/list"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/gaurav-gosain/ollamanager/policy"
+)
+
+// applyPolicy marks the installable models the policy does not allow with
+// the reason. Only the allow and deny lists are checked here, the size and
+// quantization rules depend on the tag and are checked before pulling.
+func applyPolicy(models []OllamaModel) error {
+	p, err := policy.Load()
+	if err != nil {
+		return err
+	}
+
+	for i, model := range models {
+		if err := p.CheckModelName(model.Name); err != nil {
+			models[i].Disallowed = err.Error()
+		}
+	}
+
+	return nil
+}
+
+// installableDelegate renders the models the policy does not allow greyed
+// out.
+type installableDelegate struct {
+	list.DefaultDelegate
+	disallowed list.DefaultDelegate
+}
+
+func newInstallableDelegate() installableDelegate {
+	d := installableDelegate{
+		DefaultDelegate: list.NewDefaultDelegate(),
+		disallowed:      list.NewDefaultDelegate(),
+	}
+
+	grey := lipgloss.Color("240")
+	styles := &d.disallowed.Styles
+	styles.NormalTitle = styles.DimmedTitle.Foreground(grey).Strikethrough(true)
+	styles.NormalDesc = styles.DimmedDesc.Foreground(grey)
+	styles.SelectedTitle = styles.SelectedTitle.Foreground(grey).Strikethrough(true)
+	styles.SelectedDesc = styles.SelectedDesc.Foreground(grey)
+
+	return d
+}
+
+func (d installableDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if model, ok := item.(OllamaModel); ok && model.Disallowed != "" {
+		d.disallowed.Render(w, m, index, item)
+		return
+	}
+
+	d.DefaultDelegate.Render(w, m, index, item)
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/gaurav-gosain/ollamanager/timeline"
+)
+
+// timelineRefresh is how often the Monitor tab redraws its charts.
+const timelineRefresh = 5 * time.Second
+
+// timelineModels caps how many models get a VRAM chart.
+const timelineModels = 4
+
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+type timelineTickMsg struct{}
+
+func timelineTick() tea.Cmd {
+	return tea.Tick(timelineRefresh, func(_ time.Time) tea.Msg {
+		return timelineTickMsg{}
+	})
+}
+
+// sparkline draws the values scaled to the largest one. NaN values, before
+// the first sample, are left blank.
+func sparkline(values []float64) string {
+	var top float64
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			top = max(top, v)
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case top == 0:
+			b.WriteRune(sparkBars[0])
+		default:
+			b.WriteRune(sparkBars[int(math.Round(v/top*float64(len(sparkBars)-1)))])
+		}
+	}
+
+	return b.String()
+}
+
+// timelineInfo charts the total loaded size, the number of loaded models and
+// the VRAM of the models loaded over the window of the ring, selected first,
+// in width columns.
+func timelineInfo(ring *timeline.Ring, selected string, width int) string {
+	samples := ring.Samples()
+	if len(samples) == 0 {
+		return lipgloss.NewStyle().Foreground(dimTextColor).Render("No samples yet")
+	}
+
+	const labelWidth, valueWidth = 8, 9
+	columns := max(width-labelWidth-valueWidth-2, 10)
+
+	now := Now()
+	since := now.Add(-ring.Window())
+	latest := samples[len(samples)-1]
+
+	row := func(label, value string, fn func(timeline.Sample) float64) string {
+		return fmt.Sprintf(
+			"%-*s %s %*s",
+			labelWidth, label,
+			sparkline(timeline.Series(samples, since, now, columns, fn)),
+			valueWidth, value,
+		)
+	}
+
+	rows := []string{
+		lipgloss.NewStyle().Foreground(dimTextColor).Render(
+			fmt.Sprintf("Last %d minutes", int(ring.Window().Minutes())),
+		),
+		"",
+		row("Loaded", humanize.Bytes(uint64(latest.Size())), func(s timeline.Sample) float64 {
+			return float64(s.Size())
+		}),
+		row("Models", fmt.Sprint(len(latest.Models)), func(s timeline.Sample) float64 {
+			return float64(len(s.Models))
+		}),
+	}
+
+	var names []string
+	if selected != "" {
+		names = append(names, selected)
+	}
+	for _, s := range samples {
+		for _, model := range s.Models {
+			if !slices.Contains(names, model.Name) {
+				names = append(names, model.Name)
+			}
+		}
+	}
+
+	for _, name := range names[:min(len(names), timelineModels)] {
+		rows = append(rows,
+			"",
+			truncateText(name, labelWidth+columns+valueWidth+2),
+			row("VRAM", humanize.Bytes(uint64(latest.VRAM(name))), func(s timeline.Sample) float64 {
+				return float64(s.VRAM(name))
+			}),
+		)
+	}
+
+	return strings.Join(rows, "\n")
+}
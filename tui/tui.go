@@ -6,11 +6,14 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/v2/progress"
 	"github.com/charmbracelet/bubbles/v2/spinner"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
+	humanize "github.com/dustin/go-humanize"
 	"github.com/ollama/ollama/api"
 )
 
@@ -26,12 +29,23 @@ var (
 const (
 	padding                  = 2
 	maxWidth                 = 80
-	LEFT_HALF_CIRCLE  string = string(0xe0b6)
-	RIGHT_HALF_CIRCLE string = string(0xe0b4)
+	LEFT_HALF_CIRCLE  string = string(rune(0xe0b6))
+	RIGHT_HALF_CIRCLE string = string(rune(0xe0b4))
+	LOCK              string = string(rune(0xf023))
 )
 
+// ErrCancelled is returned by InstallModel when the user quits before the
+// action finished.
+var ErrCancelled = errors.New("user quit mid download :(")
+
 type progressErrMsg struct{ err error }
 
+// ProgressError returns a message that stops InstallModel with err, for
+// actions that fail while their progress is being shown.
+func ProgressError(err error) tea.Msg {
+	return progressErrMsg{err: err}
+}
+
 func finalPause() tea.Cmd {
 	return tea.Tick(time.Millisecond*750, func(_ time.Time) tea.Msg {
 		return nil
@@ -45,6 +59,17 @@ type InstallModel struct {
 	Spinner       spinner.Model
 	Progress      progress.Model
 	isDownloading bool
+	layers        map[string]int64
+}
+
+// Transferred returns the number of bytes downloaded so far, summed over all
+// layers.
+func (m InstallModel) Transferred() int64 {
+	var total int64
+	for _, completed := range m.layers {
+		total += completed
+	}
+	return total
 }
 
 var spinners = []spinner.Spinner{
@@ -61,11 +86,34 @@ var spinners = []spinner.Spinner{
 	spinner.Hamburger,
 }
 
+// PickSpinner chooses the spinner used for each progress step. It picks a
+// random one by default and can be replaced for deterministic rendering.
+var PickSpinner = func() spinner.Spinner {
+	return spinners[rand.Intn(len(spinners))]
+}
+
+// Now is the clock relative times ("2 hours ago") are shown against. It can
+// be replaced for deterministic rendering.
+var Now = time.Now
+
+// relTime formats t relative to Now, like humanize.Time.
+func relTime(t time.Time) string {
+	return humanize.RelTime(t, Now(), "ago", "from now")
+}
+
+// capitalize upper cases the first rune of s.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
 func InitSpinner() spinner.Model {
 	s := spinner.New()
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("69"))
-	randomSpinner := spinners[rand.Intn(len(spinners))]
-	s.Spinner = randomSpinner
+	s.Spinner = PickSpinner()
 	return s
 }
 
@@ -78,7 +126,7 @@ func (m InstallModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
-			m.Err = errors.New("user quit mid download :(")
+			m.Err = ErrCancelled
 			return m, tea.Quit
 		default:
 			return m, nil
@@ -98,7 +146,8 @@ func (m InstallModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case api.ProgressResponse:
 		var cmds []tea.Cmd
 
-		if m.rawStatus != msg.Status {
+		// an empty status only reports progress of the current step
+		if msg.Status != "" && m.rawStatus != msg.Status {
 			m.Spinner = InitSpinner()
 			cmds = append(cmds, m.Spinner.Tick)
 			if m.status != "" {
@@ -117,12 +166,28 @@ func (m InstallModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.status = "Success!"
 				cmds = append(cmds, tea.Sequence(finalPause(), tea.Quit))
 			default:
-				m.status = "Downloading... (" + msg.Status + ") "
+				if msg.Total != 0 && strings.HasPrefix(msg.Status, "pulling") {
+					m.status = "Downloading... (" + msg.Status + ") "
+				} else if msg.Total != 0 && strings.HasPrefix(msg.Status, "pushing") {
+					m.status = "Uploading... (" + msg.Status + ") "
+				} else {
+					// e.g. "using existing layer sha256:..." while creating a model
+					m.status = capitalize(msg.Status) + "..."
+				}
 			}
 		}
 
 		m.isDownloading = msg.Total != 0
-		m.rawStatus = msg.Status
+		if msg.Status != "" {
+			m.rawStatus = msg.Status
+		}
+
+		if msg.Digest != "" {
+			if m.layers == nil {
+				m.layers = map[string]int64{}
+			}
+			m.layers[msg.Digest] = msg.Completed
+		}
 
 		if m.isDownloading {
 			progress := float64(msg.Completed) / float64(msg.Total)
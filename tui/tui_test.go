@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/ollama/ollama/api"
+)
+
+func TestCapitalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"using existing layer", "Using existing layer"},
+		{"élaboration", "Élaboration"},
+		{"日本", "日本"},
+		{"A", "A"},
+	}
+
+	for _, tt := range tests {
+		if got := capitalize(tt.in); got != tt.want {
+			t.Errorf("capitalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestInstallModelEmptyStatus(t *testing.T) {
+	m, _ := InstallModel{}.Update(api.ProgressResponse{Status: "pulling manifest"})
+	m, _ = m.Update(api.ProgressResponse{})
+
+	// the previous status is kept
+	if status := m.(InstallModel).status; status != "Pulling manifest..." {
+		t.Fatalf("status = %q", status)
+	}
+}
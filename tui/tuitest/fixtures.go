@@ -0,0 +1,128 @@
+package tuitest
+
+import (
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/history"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/ollama/ollama/api"
+)
+
+// Installable returns library models for the Install tab.
+func Installable() []tui.OllamaModel {
+	return []tui.OllamaModel{
+		{
+			Name:      "llama3.2",
+			Desc:      "Meta's Llama 3.2 goes small with 1B and 3B models.",
+			Pulls:     "5.1M",
+			Tags:      "63",
+			Updated:   "2 months ago",
+			ExtraInfo: []string{"tools", "1b", "3b"},
+		},
+		{
+			Name:      "nomic-embed-text",
+			Desc:      "A high-performing open embedding model with a large token context window.",
+			Pulls:     "18.4M",
+			Tags:      "3",
+			Updated:   "9 months ago",
+			ExtraInfo: []string{"embedding"},
+		},
+	}
+}
+
+// Installed returns models for the Manage tab. Times are relative to Now so
+// humanized durations stay stable.
+func Installed() []tui.InstalledOllamaModel {
+	return []tui.InstalledOllamaModel{
+		{
+			Name:       "llama3.2:latest",
+			Model:      "llama3.2:latest",
+			ModifiedAt: Now.Add(-49 * time.Hour),
+			Size:       2019393189,
+			Digest:     "a80c4f17acd55265feec403c7aef86be0c25983ab279d83f3bcd3abbcb5b8b72",
+			Details: api.ModelDetails{
+				Format:            "gguf",
+				Family:            "llama",
+				Families:          []string{"llama"},
+				ParameterSize:     "3.2B",
+				QuantizationLevel: "Q4_K_M",
+			},
+		},
+		{
+			Name:       "llava:7b",
+			Model:      "llava:7b",
+			ModifiedAt: Now.Add(-15 * 24 * time.Hour),
+			Size:       4733363377,
+			Digest:     "8dd30f6b0cb19f555f2c7a7ebda861449ea2cc76bf1f44e262931f45fc81d081",
+			Details: api.ModelDetails{
+				Format:            "gguf",
+				Family:            "llama",
+				Families:          []string{"llama", "clip"},
+				ParameterSize:     "7B",
+				QuantizationLevel: "Q4_0",
+			},
+		},
+	}
+}
+
+// Usage returns the pins and usage of the Installed models.
+func Usage() map[string]tui.ModelUsage {
+	return map[string]tui.ModelUsage{
+		"llama3.2:latest": {
+			Pinned:   true,
+			LastUsed: Now.Add(-3 * time.Hour),
+			Loaded:   95 * time.Minute,
+		},
+		"llava:7b": {
+			Unused: true,
+		},
+	}
+}
+
+// Running returns models for the Monitor tab.
+func Running() []tui.RunningOllamaModel {
+	return []tui.RunningOllamaModel{
+		{
+			Name:      "llama3.2:latest",
+			Model:     "llama3.2:latest",
+			Size:      3077459968,
+			SizeVRAM:  3077459968,
+			Digest:    "a80c4f17acd55265feec403c7aef86be0c25983ab279d83f3bcd3abbcb5b8b72",
+			ExpiresAt: Now.Add(4*time.Minute + 30*time.Second),
+			Details: api.ModelDetails{
+				Format:            "gguf",
+				Family:            "llama",
+				ParameterSize:     "3.2B",
+				QuantizationLevel: "Q4_K_M",
+			},
+		},
+	}
+}
+
+// History returns entries for the History tab, newest first.
+func History() []tui.HistoryEntry {
+	return []tui.HistoryEntry{
+		{
+			Time:         Now.Add(-2 * time.Hour),
+			Host:         "http://127.0.0.1:11434",
+			User:         "ollama",
+			Action:       "Update",
+			Model:        "llama3.2:latest",
+			DigestBefore: "8dd30f6b0cb19f555f2c7a7ebda861449ea2cc76bf1f44e262931f45fc81d081",
+			DigestAfter:  "a80c4f17acd55265feec403c7aef86be0c25983ab279d83f3bcd3abbcb5b8b72",
+			Bytes:        2019393189,
+			Duration:     95 * time.Second,
+			Outcome:      history.SUCCESS,
+		},
+		{
+			Time:     Now.Add(-26 * time.Hour),
+			Host:     "http://127.0.0.1:11434",
+			User:     "ollama",
+			Action:   "Install",
+			Model:    "qwen2.5:72b",
+			Duration: 3 * time.Second,
+			Outcome:  history.FAILURE,
+			Error:    "pull model manifest: file does not exist",
+		},
+	}
+}
@@ -0,0 +1,189 @@
+// Package tuitest renders bubbletea models deterministically and compares the
+// output against golden files, so views like tui.ModelSelector can be
+// checked at several terminal sizes without a terminal.
+//
+//	func TestSelector(t *testing.T) {
+//		restore := tuitest.Deterministic()
+//		defer restore()
+//
+//		tuitest.GoldenSizes(t, "manage", func() tea.Model {
+//			return tui.NewModelSelector(tabs, actions, nil, tuitest.Installed(), tuitest.Usage(), nil, nil, nil, nil)
+//		}, tuitest.Key("?"))
+//	}
+//
+// Golden files live in testdata/<name>.golden relative to the test and are
+// (re)written when OLLAMANAGER_UPDATE_GOLDEN is set.
+package tuitest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/v2/spinner"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gaurav-gosain/ollamanager/tui"
+	"github.com/muesli/termenv"
+)
+
+// Size is a terminal size in cells.
+type Size struct {
+	Width  int
+	Height int
+}
+
+// Sizes covers a narrow terminal (no info pane), a terminal just past the info
+// pane breakpoint and a wide one.
+var Sizes = []Size{
+	{Width: 80, Height: 24},
+	{Width: 100, Height: 30},
+	{Width: 160, Height: 45},
+}
+
+// Now is the fixed time views are rendered at by Deterministic, the fixtures
+// are relative to it.
+var Now = time.Date(2024, time.November, 20, 12, 0, 0, 0, time.UTC)
+
+// Deterministic fixes every source of nondeterminism in the tui package: the
+// lipgloss v1 color profile (Render downsamples lipgloss v2 output) and background detection, the random spinner and
+// the clock relative times are shown against. It returns a function
+// restoring the previous settings.
+func Deterministic() (restore func()) {
+	profile := lipgloss.ColorProfile()
+	darkBackground := lipgloss.HasDarkBackground()
+	pickSpinner := tui.PickSpinner
+	now := tui.Now
+
+	lipgloss.SetColorProfile(termenv.Ascii)
+	lipgloss.SetHasDarkBackground(true)
+	tui.PickSpinner = func() spinner.Spinner { return spinner.Line }
+	tui.Now = func() time.Time { return Now }
+
+	return func() {
+		lipgloss.SetColorProfile(profile)
+		lipgloss.SetHasDarkBackground(darkBackground)
+		tui.PickSpinner = pickSpinner
+		tui.Now = now
+	}
+}
+
+// Resize returns the message bubbletea sends when the terminal is resized.
+func Resize(width, height int) tea.Msg {
+	return tea.WindowSizeMsg{Width: width, Height: height}
+}
+
+var namedKeys = map[string]tea.Key{
+	"enter":     {Code: tea.KeyEnter},
+	"tab":       {Code: tea.KeyTab},
+	"shift+tab": {Code: tea.KeyTab, Mod: tea.ModShift},
+	"esc":       {Code: tea.KeyEscape},
+	"up":        {Code: tea.KeyUp},
+	"down":      {Code: tea.KeyDown},
+	"left":      {Code: tea.KeyLeft},
+	"right":     {Code: tea.KeyRight},
+	"space":     {Code: tea.KeySpace, Text: " "},
+	"ctrl+c":    {Code: 'c', Mod: tea.ModCtrl},
+}
+
+// Key returns a key press message for either a named key ("enter",
+// "shift+tab", ...) or a single printable character.
+func Key(name string) tea.Msg {
+	if k, ok := namedKeys[name]; ok {
+		return tea.KeyPressMsg(k)
+	}
+
+	r := []rune(name)
+	return tea.KeyPressMsg(tea.Key{Code: r[0], Text: name})
+}
+
+// Keys returns a key press message for every character of s.
+func Keys(s string) []tea.Msg {
+	var msgs []tea.Msg
+	for _, r := range s {
+		msgs = append(msgs, Key(string(r)))
+	}
+
+	return msgs
+}
+
+// Render initializes the model, feeds it the messages in order and returns
+// the final model and its view. Commands returned by the model are dropped so
+// the result never depends on timers or I/O. The view is downsampled to the
+// Ascii color profile, as a renderer would for a terminal without colors:
+// lipgloss v2 styles always render in true color and leave downsampling to
+// the renderer, so Deterministic can't pin them.
+func Render(m tea.Model, msgs ...tea.Msg) (tea.Model, string) {
+	m, _ = m.Init()
+	for _, msg := range msgs {
+		m, _ = m.Update(msg)
+	}
+
+	var view strings.Builder
+	w := colorprofile.NewWriter(&view, nil)
+	w.Profile = colorprofile.Ascii
+	_, _ = w.WriteString(m.View())
+
+	return m, view.String()
+}
+
+// Golden compares got to testdata/<name>.golden, failing the test with both
+// versions on mismatch.
+func Golden(t testing.TB, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if os.Getenv("OLLAMANAGER_UPDATE_GOLDEN") != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("missing golden file %s, run with OLLAMANAGER_UPDATE_GOLDEN=1 to create it", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(want) != got {
+		t.Errorf(
+			"%s does not match the rendered view\n--- want\n%s\n--- got\n%s",
+			path, visible(string(want)), visible(got),
+		)
+	}
+}
+
+// GoldenSizes renders a fresh model at every size in Sizes, followed by msgs,
+// and compares each view to the golden file <name>_<width>x<height>.
+func GoldenSizes(t *testing.T, name string, newModel func() tea.Model, msgs ...tea.Msg) {
+	t.Helper()
+
+	for _, size := range Sizes {
+		sizeName := fmt.Sprintf("%s_%dx%d", name, size.Width, size.Height)
+		t.Run(sizeName, func(t *testing.T) {
+			_, view := Render(
+				newModel(),
+				append([]tea.Msg{Resize(size.Width, size.Height)}, msgs...)...,
+			)
+			Golden(t, sizeName, view)
+		})
+	}
+}
+
+// visible makes escape sequences readable in failure output.
+func visible(s string) string {
+	return strings.ReplaceAll(s, "\x1b", `\x1b`)
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/list"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// InstalledOrder is the order of the Manage tab list.
+type InstalledOrder string
+
+const (
+	ORDER_MODIFIED  InstalledOrder = "modified"
+	ORDER_LAST_USED InstalledOrder = "last used"
+	ORDER_LOADED    InstalledOrder = "loaded time"
+	ORDER_SIZE      InstalledOrder = "size"
+)
+
+// installedOrders lists the orders in the sequence the sort key cycles
+// through them. Models are listed as the Ollama server returns them, most
+// recently modified first, by default.
+var installedOrders = []InstalledOrder{
+	ORDER_MODIFIED,
+	ORDER_LAST_USED,
+	ORDER_LOADED,
+	ORDER_SIZE,
+}
+
+const installedTitle = "Pick an installed Model..."
+
+// nextOrder returns the order after the current one.
+func nextOrder(order InstalledOrder) InstalledOrder {
+	i := slices.Index(installedOrders, order)
+	return installedOrders[(i+1)%len(installedOrders)]
+}
+
+// sortInstalled orders the models so the ones most likely to be dead weight
+// come first: least recently used, least loaded or largest.
+func sortInstalled(models []installedItem, order InstalledOrder) {
+	switch order {
+	case ORDER_LAST_USED:
+		slices.SortStableFunc(models, func(a, b installedItem) int {
+			return a.LastActive().Compare(b.LastActive())
+		})
+	case ORDER_LOADED:
+		slices.SortStableFunc(models, func(a, b installedItem) int {
+			return cmp.Compare(a.Loaded, b.Loaded)
+		})
+	case ORDER_SIZE:
+		slices.SortStableFunc(models, func(a, b installedItem) int {
+			return cmp.Compare(b.Size, a.Size)
+		})
+	}
+}
+
+// refreshInstalled rebuilds the Manage tab list from the installed models
+// with the current order and filter.
+func (m *ModelSelector) refreshInstalled() tea.Cmd {
+	models := slices.Clone(m.installedModels)
+	if m.unusedOnly {
+		models = slices.DeleteFunc(models, func(model installedItem) bool {
+			return !model.Unused
+		})
+	}
+	sortInstalled(models, m.installedOrder)
+
+	items := make([]list.Item, len(models))
+	for i, model := range models {
+		items[i] = model
+	}
+
+	var details []string
+	if m.installedOrder != ORDER_MODIFIED {
+		details = append(details, "by "+string(m.installedOrder))
+	}
+	if m.unusedOnly {
+		details = append(details, "unused only")
+	}
+
+	m.installedList.Title = installedTitle
+	if len(details) > 0 {
+		m.installedList.Title += " (" + strings.Join(details, ", ") + ")"
+	}
+
+	m.installedList.ResetSelected()
+	return m.installedList.SetItems(items)
+}
@@ -0,0 +1,216 @@
+// Package usage records when models were last loaded by the Ollama server and
+// for how long, by sampling the list of running models.
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gaurav-gosain/ollamanager/config"
+	"github.com/ollama/ollama/api"
+)
+
+// Record is the usage of a single model.
+type Record struct {
+	// LastUsed is the last time the model was seen loaded.
+	LastUsed time.Time `json:"last_used"`
+	// Loaded is the total time the model was seen loaded.
+	Loaded time.Duration `json:"loaded"`
+}
+
+// Log is the usage of every model seen loaded since tracking started.
+type Log struct {
+	Since  time.Time         `json:"since"`
+	Models map[string]Record `json:"models"`
+}
+
+// Path returns the default log location inside the ollamanager data dir.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "usage.json"), nil
+}
+
+// Read returns the log at path. A missing log is empty.
+func Read(path string) (Log, error) {
+	var l Log
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return l, err
+	}
+
+	err = json.Unmarshal(data, &l)
+	return l, err
+}
+
+// Write replaces the log at path, creating it if needed. Use Update to
+// modify the log, other processes may be writing it.
+func Write(path string, l Log) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so readers never see a partial log
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".usage-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// staleLock is how old a lock file must be to be considered left over by a
+// crashed process. Updates hold the lock for milliseconds.
+const staleLock = 30 * time.Second
+
+// lock takes an exclusive lock on the log at path by creating path.lock, so
+// that samplers in several processes (e.g. `ollamanager track-usage` and the
+// TUI) never overwrite each other's samples. It gives up after timeout.
+func lock(path string, timeout time.Duration) (unlock func(), err error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(timeout)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another process", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Update applies fn to the log at path while holding its lock, and writes it
+// back.
+func Update(path string, fn func(*Log)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	unlock, err := lock(path, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	l, err := Read(path)
+	if err != nil {
+		return err
+	}
+
+	fn(&l)
+
+	return Write(path, l)
+}
+
+// Sample folds a snapshot of the running models taken at now into the log.
+// The time since a model was last seen counts as loaded time when it is at
+// most maxGap, longer gaps mean nobody was sampling.
+func (l *Log) Sample(running []api.ProcessModelResponse, now time.Time, maxGap time.Duration) {
+	if l.Since.IsZero() {
+		l.Since = now
+	}
+	if l.Models == nil {
+		l.Models = map[string]Record{}
+	}
+
+	for _, model := range running {
+		record := l.Models[model.Name]
+		if gap := now.Sub(record.LastUsed); gap > 0 && gap <= maxGap {
+			record.Loaded += gap
+		}
+		record.LastUsed = now
+		l.Models[model.Name] = record
+	}
+}
+
+// Sampler periodically records the running models in the log at path.
+type Sampler struct {
+	client *api.Client
+	path   string
+
+	mu sync.Mutex
+}
+
+// NewSampler returns a sampler for the Ollama server behind client.
+func NewSampler(client *api.Client, path string) *Sampler {
+	return &Sampler{
+		client: client,
+		path:   path,
+	}
+}
+
+// Poll samples the running models once. Models are counted as loaded for up
+// to maxGap since they were last seen.
+func (s *Sampler) Poll(ctx context.Context, maxGap time.Duration) error {
+	running, err := s.client.ListRunning(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Update(s.path, func(l *Log) {
+		l.Sample(running.Models, time.Now(), maxGap)
+	})
+}
+
+// Run samples every interval until the context is cancelled.
+func (s *Sampler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// allow some jitter between samples, but not a whole missed one
+	maxGap := interval + interval/2
+
+	for {
+		_ = s.Poll(ctx, maxGap)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateSerializesWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+
+	const writers = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- Update(path, func(l *Log) {
+				if l.Models == nil {
+					l.Models = map[string]Record{}
+				}
+				record := l.Models["llama3.2:latest"]
+				record.Loaded += time.Second
+				l.Models["llama3.2:latest"] = record
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Models["llama3.2:latest"].Loaded; got != writers*time.Second {
+		t.Errorf("Loaded = %s, want %s, updates were lost", got, writers*time.Second)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestLockTimesOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+
+	unlock, err := lock(path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	if _, err := lock(path, 50*time.Millisecond); err == nil {
+		t.Error("lock succeeded while held")
+	}
+}
+
+func TestLockTakesOverStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+
+	if err := os.WriteFile(path+".lock", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLock)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := lock(path, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("stale lock was not taken over: %s", err)
+	}
+	unlock()
+}